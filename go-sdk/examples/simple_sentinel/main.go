@@ -67,7 +67,7 @@ func main() {
 
 	log.Printf("Starting %s, connecting to %s", sentinel.Name, hubURL)
 
-	if err := sentinel.Start(ctx, hubURL); err != nil {
+	if err := sentinel.Start(ctx, starlight.StaticRegistry(hubURL)); err != nil {
 		if err != context.Canceled {
 			log.Fatalf("Sentinel error: %v", err)
 		}
@@ -1,11 +1,15 @@
 package starlight
 
 import (
+	"crypto"
+	"crypto/ecdsa"
 	"crypto/hmac"
+	"crypto/rsa"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"strings"
 	"time"
 )
@@ -15,6 +19,20 @@ type JWTClaims struct {
 	IssuedAt   int64  `json:"iat"`
 	Expiration int64  `json:"exp"`
 	Subject    string `json:"sub"`
+
+	// Issuer identifies the party that issued the token (e.g. an external
+	// identity provider).
+	Issuer string `json:"iss,omitempty"`
+
+	// Audience identifies the intended recipient of the token.
+	Audience string `json:"aud,omitempty"`
+}
+
+// jwtHeader is the JOSE header of a Starlight JWT token.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid,omitempty"`
 }
 
 // GenerateToken creates a JWT token for authentication.
@@ -51,24 +69,88 @@ func GenerateToken(secret, subject string, expirySeconds int64) (string, error)
 	return message + "." + signature, nil
 }
 
-// ValidateToken verifies a JWT token and returns the claims.
+// ValidateToken verifies a JWT token against a single shared secret. It's a
+// thin wrapper around ValidateTokenWithKeySource using StaticHMAC, kept for
+// callers that only ever deal with one HS256 secret.
 func ValidateToken(token, secret string) (*JWTClaims, error) {
+	return ValidateTokenWithKeySource(token, StaticHMAC([]byte(secret)))
+}
+
+// ValidateTokenWithKeySource verifies a JWT token signed with RS256, ES256,
+// or HS256 against keys resolved from ks, using the token header's kid to
+// select the key and alg to select the verifier. This is the one
+// verification path both ValidateToken and Sentinel.VerifyPeerToken funnel
+// through.
+func ValidateTokenWithKeySource(token string, ks KeySource) (*JWTClaims, error) {
 	parts := strings.Split(token, ".")
 	if len(parts) != 3 {
 		return nil, fmt.Errorf("invalid token format")
 	}
 
-	// Verify signature
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode header: %w", err)
+	}
+	var header jwtHeader
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, fmt.Errorf("failed to parse header: %w", err)
+	}
+
+	key, alg, err := ks.Key(header.Kid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve key for kid %q: %w", header.Kid, err)
+	}
+	if header.Alg != alg {
+		return nil, fmt.Errorf("token alg %q does not match key alg %q", header.Alg, alg)
+	}
+
 	message := parts[0] + "." + parts[1]
-	h := hmac.New(sha256.New, []byte(secret))
-	h.Write([]byte(message))
-	expectedSig := base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signature: %w", err)
+	}
 
-	if !hmac.Equal([]byte(parts[2]), []byte(expectedSig)) {
-		return nil, fmt.Errorf("invalid signature")
+	switch header.Alg {
+	case "HS256":
+		secret, ok := key.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("key for HS256 must be a shared secret")
+		}
+		h := hmac.New(sha256.New, secret)
+		h.Write([]byte(message))
+		if !hmac.Equal(sig, h.Sum(nil)) {
+			return nil, fmt.Errorf("invalid signature")
+		}
+
+	case "RS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("key for RS256 must be an RSA public key")
+		}
+		hashed := sha256.Sum256([]byte(message))
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, hashed[:], sig); err != nil {
+			return nil, fmt.Errorf("invalid signature: %w", err)
+		}
+
+	case "ES256":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("key for ES256 must be an ECDSA public key")
+		}
+		if len(sig) != 64 {
+			return nil, fmt.Errorf("invalid ES256 signature length")
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		sVal := new(big.Int).SetBytes(sig[32:])
+		hashed := sha256.Sum256([]byte(message))
+		if !ecdsa.Verify(pub, hashed[:], r, sVal) {
+			return nil, fmt.Errorf("invalid signature")
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported alg: %s", header.Alg)
 	}
 
-	// Decode payload
 	payloadJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode payload: %w", err)
@@ -79,7 +161,6 @@ func ValidateToken(token, secret string) (*JWTClaims, error) {
 		return nil, fmt.Errorf("failed to parse claims: %w", err)
 	}
 
-	// Check expiration
 	if time.Now().Unix() > claims.Expiration {
 		return nil, fmt.Errorf("token expired")
 	}
@@ -102,3 +183,22 @@ func (s *Sentinel) WithAuth(tokenOrSecret string, isSecret bool) *Sentinel {
 	}
 	return s
 }
+
+// WithAuthKeySource configures the sentinel to validate peer tokens using
+// ks instead of (or in addition to) issuing its own HMAC token. Use this
+// when the sentinel is handed a ready-made token by an external identity
+// provider, or when it needs to verify tokens presented by peers.
+func (s *Sentinel) WithAuthKeySource(ks KeySource) *Sentinel {
+	s.authKeySource = ks
+	return s
+}
+
+// VerifyPeerToken validates a token presented by another sentinel (e.g.
+// during a relayed peer message) using the KeySource configured via
+// WithAuthKeySource.
+func (s *Sentinel) VerifyPeerToken(token string) (*JWTClaims, error) {
+	if s.authKeySource == nil {
+		return nil, fmt.Errorf("no key source configured, call WithAuthKeySource first")
+	}
+	return ValidateTokenWithKeySource(token, s.authKeySource)
+}
@@ -0,0 +1,109 @@
+package starlight
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"testing"
+)
+
+func TestGenerateAndValidateToken(t *testing.T) {
+	token, err := GenerateToken("shared-secret", "sentinel-a", 3600)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	claims, err := ValidateToken(token, "shared-secret")
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if claims.Subject != "sentinel-a" {
+		t.Errorf("Subject = %q, want %q", claims.Subject, "sentinel-a")
+	}
+}
+
+func TestValidateTokenWrongSecret(t *testing.T) {
+	token, err := GenerateToken("shared-secret", "sentinel-a", 3600)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	if _, err := ValidateToken(token, "wrong-secret"); err == nil {
+		t.Fatal("ValidateToken with wrong secret should fail")
+	}
+}
+
+func TestValidateTokenExpired(t *testing.T) {
+	token, err := GenerateToken("shared-secret", "sentinel-a", -10)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	if _, err := ValidateToken(token, "shared-secret"); err == nil {
+		t.Fatal("ValidateToken with expired token should fail")
+	}
+}
+
+func TestValidateTokenViaKeySourceMatchesValidateToken(t *testing.T) {
+	token, err := GenerateToken("shared-secret", "sentinel-a", 3600)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	byToken, err := ValidateToken(token, "shared-secret")
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+
+	byKeySource, err := ValidateTokenWithKeySource(token, StaticHMAC([]byte("shared-secret")))
+	if err != nil {
+		t.Fatalf("ValidateTokenWithKeySource: %v", err)
+	}
+
+	if byToken.Subject != byKeySource.Subject || byToken.Expiration != byKeySource.Expiration {
+		t.Errorf("ValidateToken and ValidateTokenWithKeySource disagree: %+v vs %+v", byToken, byKeySource)
+	}
+}
+
+func TestDecodeJWKRSA(t *testing.T) {
+	k := jwk{
+		Kty: "RSA",
+		Kid: "rsa-1",
+		N:   base64.RawURLEncoding.EncodeToString([]byte{0x01, 0x00, 0x01, 0x02}),
+		E:   base64.RawURLEncoding.EncodeToString([]byte{0x01, 0x00, 0x01}),
+	}
+
+	key, alg, err := decodeJWK(k)
+	if err != nil {
+		t.Fatalf("decodeJWK: %v", err)
+	}
+	if alg != "RS256" {
+		t.Errorf("alg = %q, want RS256", alg)
+	}
+	if _, ok := key.(*rsa.PublicKey); !ok {
+		t.Errorf("decoded key type = %T, want *rsa.PublicKey", key)
+	}
+}
+
+func TestDecodeJWKUnsupportedKty(t *testing.T) {
+	if _, _, err := decodeJWK(jwk{Kty: "oct"}); err == nil {
+		t.Fatal("decodeJWK with unsupported kty should fail")
+	}
+}
+
+func TestStaticHMACIgnoresKid(t *testing.T) {
+	ks := StaticHMAC([]byte("secret"))
+
+	for _, kid := range []string{"", "any-kid", "another-kid"} {
+		key, alg, err := ks.Key(kid)
+		if err != nil {
+			t.Fatalf("Key(%q): %v", kid, err)
+		}
+		if alg != "HS256" {
+			t.Errorf("Key(%q) alg = %q, want HS256", kid, alg)
+		}
+		secret, ok := key.([]byte)
+		if !ok || string(secret) != "secret" {
+			t.Errorf("Key(%q) key = %v, want the configured secret", kid, key)
+		}
+	}
+}
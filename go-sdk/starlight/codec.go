@@ -0,0 +1,150 @@
+package starlight
+
+import (
+	"encoding/json"
+	"fmt"
+
+	golangproto "github.com/golang/protobuf/proto"
+	"github.com/gorilla/websocket"
+	starlightpb "github.com/starlight-protocol/starlight-go/starlight/proto"
+)
+
+// Codec controls how a Message is serialized for the wire and which
+// WebSocket frame type carries it.
+type Codec interface {
+	Marshal(msg *Message) ([]byte, error)
+	Unmarshal(data []byte, msg *Message) error
+	ContentType() string
+	WSMessageType() int
+}
+
+// JSONCodec encodes messages as JSON text frames. It is the protocol
+// default and is always available during the registration handshake.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(msg *Message) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func (JSONCodec) Unmarshal(data []byte, msg *Message) error {
+	return json.Unmarshal(data, msg)
+}
+
+func (JSONCodec) ContentType() string {
+	return "application/json"
+}
+
+func (JSONCodec) WSMessageType() int {
+	return websocket.TextMessage
+}
+
+// ProtoCodec encodes messages as protobuf binary frames using the
+// generated types in starlight/proto. Params and Result are carried as
+// opaque JSON bytes within the protobuf envelope, so method handlers are
+// unaffected by which codec is negotiated.
+type ProtoCodec struct{}
+
+func (ProtoCodec) Marshal(msg *Message) ([]byte, error) {
+	pbMsg, err := toProtoMessage(msg)
+	if err != nil {
+		return nil, err
+	}
+	return golangproto.Marshal(pbMsg)
+}
+
+func (ProtoCodec) Unmarshal(data []byte, msg *Message) error {
+	var pbMsg starlightpb.Message
+	if err := golangproto.Unmarshal(data, &pbMsg); err != nil {
+		return fmt.Errorf("failed to unmarshal protobuf message: %w", err)
+	}
+	return fromProtoMessage(&pbMsg, msg)
+}
+
+func (ProtoCodec) ContentType() string {
+	return "application/x-protobuf"
+}
+
+func (ProtoCodec) WSMessageType() int {
+	return websocket.BinaryMessage
+}
+
+// toProtoMessage converts a Message into its protobuf wire representation.
+// Shared by ProtoCodec and GRPCTransport, which both speak starlightpb.Message.
+func toProtoMessage(msg *Message) (*starlightpb.Message, error) {
+	pbMsg := &starlightpb.Message{
+		Jsonrpc:     msg.JSONRPC,
+		Method:      msg.Method,
+		Params:      []byte(msg.Params),
+		Id:          msg.ID,
+		Result:      []byte(msg.Result),
+		Seq:         msg.Seq,
+		Traceparent: msg.Traceparent,
+	}
+	if msg.Error != nil {
+		pbMsg.Error = &starlightpb.RPCError{
+			Code:    int32(msg.Error.Code),
+			Message: msg.Error.Message,
+		}
+		if msg.Error.Data != nil {
+			data, err := json.Marshal(msg.Error.Data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal error data: %w", err)
+			}
+			pbMsg.Error.Data = data
+		}
+	}
+	return pbMsg, nil
+}
+
+// fromProtoMessage populates msg from its protobuf wire representation.
+func fromProtoMessage(pbMsg *starlightpb.Message, msg *Message) error {
+	msg.JSONRPC = pbMsg.Jsonrpc
+	msg.Method = pbMsg.Method
+	msg.Params = pbMsg.Params
+	msg.ID = pbMsg.Id
+	msg.Result = pbMsg.Result
+	msg.Seq = pbMsg.Seq
+	msg.Traceparent = pbMsg.Traceparent
+	if pbMsg.Error != nil {
+		msg.Error = &RPCError{
+			Code:    int(pbMsg.Error.Code),
+			Message: pbMsg.Error.Message,
+		}
+		if len(pbMsg.Error.Data) > 0 {
+			var data any
+			if err := json.Unmarshal(pbMsg.Error.Data, &data); err != nil {
+				return fmt.Errorf("failed to unmarshal error data: %w", err)
+			}
+			msg.Error.Data = data
+		}
+	}
+	return nil
+}
+
+// supportedCodecs lists the codecs this SDK can negotiate, keyed by
+// ContentType, in the order advertised during registration.
+var supportedCodecs = []Codec{
+	JSONCodec{},
+	ProtoCodec{},
+}
+
+// codecByContentType looks up a supported codec by its negotiated content
+// type, returning false if the SDK doesn't implement it.
+func codecByContentType(contentType string) (Codec, bool) {
+	for _, c := range supportedCodecs {
+		if c.ContentType() == contentType {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// supportedCodecContentTypes returns the content types advertised in
+// RegistrationParams.Codecs.
+func supportedCodecContentTypes() []string {
+	types := make([]string, len(supportedCodecs))
+	for i, c := range supportedCodecs {
+		types[i] = c.ContentType()
+	}
+	return types
+}
@@ -0,0 +1,77 @@
+package starlight
+
+import "testing"
+
+func TestJSONCodecRoundTrip(t *testing.T) {
+	want := &Message{
+		JSONRPC:     "2.0",
+		Method:      "starlight.pulse",
+		ID:          "req-1",
+		Seq:         42,
+		Traceparent: "00-trace-span-01",
+	}
+
+	codec := JSONCodec{}
+	data, err := codec.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got Message
+	if err := codec.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got.Method != want.Method || got.Seq != want.Seq || got.Traceparent != want.Traceparent {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
+
+func TestProtoCodecRoundTrip(t *testing.T) {
+	want := &Message{
+		JSONRPC:     "2.0",
+		Method:      "starlight.ack",
+		Params:      []byte(`{"layer":"test"}`),
+		ID:          "req-2",
+		Seq:         7,
+		Traceparent: "00-abc123-def456-01",
+		Error: &RPCError{
+			Code:    400,
+			Message: "bad request",
+			Data:    map[string]any{"reason": "timeout"},
+		},
+	}
+
+	codec := ProtoCodec{}
+	data, err := codec.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var got Message
+	if err := codec.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if got.Method != want.Method || got.Seq != want.Seq || got.Traceparent != want.Traceparent {
+		t.Errorf("round trip envelope = %+v, want %+v", got, want)
+	}
+	if string(got.Params) != string(want.Params) {
+		t.Errorf("Params = %q, want %q", got.Params, want.Params)
+	}
+	if got.Error == nil || got.Error.Code != want.Error.Code || got.Error.Message != want.Error.Message {
+		t.Errorf("Error = %+v, want %+v", got.Error, want.Error)
+	}
+}
+
+func TestCodecByContentType(t *testing.T) {
+	if _, ok := codecByContentType("application/json"); !ok {
+		t.Error("expected application/json to resolve to a codec")
+	}
+	if _, ok := codecByContentType("application/x-protobuf"); !ok {
+		t.Error("expected application/x-protobuf to resolve to a codec")
+	}
+	if _, ok := codecByContentType("application/x-unsupported"); ok {
+		t.Error("expected unsupported content type to not resolve")
+	}
+}
@@ -11,7 +11,7 @@
 //	    // Your obstacle detection logic
 //	    return sentinel.Clear()
 //	}
-//	sentinel.Start("ws://localhost:8080")
+//	sentinel.Start(ctx, starlight.StaticRegistry("ws://localhost:8080"))
 //
 // # Protocol Overview
 //
@@ -0,0 +1,82 @@
+package starlight
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	starlightpb "github.com/starlight-protocol/starlight-go/starlight/proto"
+)
+
+// GRPCTransport dials the Hub's bidirectional SentinelService.Session RPC
+// instead of a WebSocket, giving HTTP/2 multiplexing, mTLS, and deadline
+// propagation for free. Messages are always protobuf-encoded on this
+// transport; there is no codec negotiation step.
+type GRPCTransport struct {
+	// DialOptions overrides the default transport credentials. If empty,
+	// "grpc://" uses insecure credentials and "grpcs://" uses TLS with the
+	// system cert pool.
+	DialOptions []grpc.DialOption
+}
+
+func (t GRPCTransport) Dial(ctx context.Context, url string) (Conn, error) {
+	target := strings.TrimPrefix(strings.TrimPrefix(url, "grpc://"), "grpcs://")
+
+	opts := t.DialOptions
+	if len(opts) == 0 {
+		if strings.HasPrefix(url, "grpcs://") {
+			opts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(defaultTLSConfig()))}
+		} else {
+			opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+		}
+	}
+
+	cc, err := grpc.DialContext(ctx, target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("grpc dial failed: %w", err)
+	}
+
+	client := starlightpb.NewSentinelServiceClient(cc)
+	stream, err := client.Session(ctx)
+	if err != nil {
+		cc.Close()
+		return nil, fmt.Errorf("grpc session failed: %w", err)
+	}
+
+	return &grpcConn{cc: cc, stream: stream}, nil
+}
+
+// grpcConn adapts a SentinelService_SessionClient stream to Conn.
+type grpcConn struct {
+	cc     *grpc.ClientConn
+	stream starlightpb.SentinelService_SessionClient
+}
+
+func (c *grpcConn) Send(msg *Message) error {
+	pbMsg, err := toProtoMessage(msg)
+	if err != nil {
+		return err
+	}
+	return c.stream.Send(pbMsg)
+}
+
+func (c *grpcConn) Recv() (*Message, error) {
+	pbMsg, err := c.stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("read error: %w", err)
+	}
+
+	var msg Message
+	if err := fromProtoMessage(pbMsg, &msg); err != nil {
+		return nil, fmt.Errorf("failed to parse message: %w", err)
+	}
+	return &msg, nil
+}
+
+func (c *grpcConn) Close() error {
+	return c.cc.Close()
+}
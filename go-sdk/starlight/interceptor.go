@@ -0,0 +1,69 @@
+package starlight
+
+import "context"
+
+// Handler processes one inbound Message. It's the terminal step of an
+// inbound interceptor chain, normally Sentinel's own method dispatch.
+type Handler func(ctx context.Context, msg *Message) error
+
+// Interceptor wraps inbound Message handling, in the style of a gRPC
+// unary interceptor: call next to continue the chain, or return an error
+// to stop it short (the terminal Handler, and any Interceptor after this
+// one, won't run). Interceptors registered via Sentinel.Use run in
+// registration order, outermost first.
+type Interceptor func(ctx context.Context, msg *Message, next Handler) error
+
+// Sender transmits one outbound Message. It's the terminal step of a send
+// interceptor chain, normally the live Conn.Send call.
+type Sender func(ctx context.Context, msg *Message) error
+
+// SendInterceptor wraps outbound Message handling. Interceptors registered
+// via Sentinel.UseSend run in registration order, outermost first.
+type SendInterceptor func(ctx context.Context, msg *Message, next Sender) error
+
+// Use registers inbound interceptors, appended to any already registered.
+// They wrap every message handleMessage dispatches to dispatchMessage.
+// Registration/challenge handshake replies never reach the chain:
+// handleMessage recognizes and consumes them by ID before building it, so
+// MetricsInterceptor, LoggingInterceptor, and similar won't see handshake
+// traffic.
+func (s *Sentinel) Use(interceptors ...Interceptor) {
+	s.inInterceptors = append(s.inInterceptors, interceptors...)
+}
+
+// UseSend registers outbound interceptors, appended to any already
+// registered. They wrap every message passed to writeMessage, after it's
+// been durably queued in Store but before the live send attempt.
+func (s *Sentinel) UseSend(interceptors ...SendInterceptor) {
+	s.outInterceptors = append(s.outInterceptors, interceptors...)
+}
+
+// chainHandler composes interceptors around terminal into a single
+// Handler, preserving registration order (the first interceptor is
+// outermost and runs first).
+func chainHandler(interceptors []Interceptor, terminal Handler) Handler {
+	h := terminal
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor := interceptors[i]
+		next := h
+		h = func(ctx context.Context, msg *Message) error {
+			return interceptor(ctx, msg, next)
+		}
+	}
+	return h
+}
+
+// chainSender composes interceptors around terminal into a single Sender,
+// preserving registration order (the first interceptor is outermost and
+// runs first).
+func chainSender(interceptors []SendInterceptor, terminal Sender) Sender {
+	sender := terminal
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor := interceptors[i]
+		next := sender
+		sender = func(ctx context.Context, msg *Message) error {
+			return interceptor(ctx, msg, next)
+		}
+	}
+	return sender
+}
@@ -0,0 +1,189 @@
+package starlight
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+)
+
+// LoggingInterceptor logs every inbound message's method and outcome at
+// logger, so the ad-hoc Logger.Printf calls scattered through dispatch can
+// be centralized into one interceptor instead.
+func LoggingInterceptor(logger *log.Logger) Interceptor {
+	return func(ctx context.Context, msg *Message, next Handler) error {
+		err := next(ctx, msg)
+		if err != nil {
+			logger.Printf("starlight: inbound %s failed: %v", msg.Method, err)
+		} else {
+			logger.Printf("starlight: inbound %s handled", msg.Method)
+		}
+		return err
+	}
+}
+
+// LoggingSendInterceptor logs every outbound message's method and outcome
+// at logger.
+func LoggingSendInterceptor(logger *log.Logger) SendInterceptor {
+	return func(ctx context.Context, msg *Message, next Sender) error {
+		err := next(ctx, msg)
+		if err != nil {
+			logger.Printf("starlight: outbound %s failed: %v", msg.Method, err)
+		} else {
+			logger.Printf("starlight: outbound %s sent", msg.Method)
+		}
+		return err
+	}
+}
+
+// messagesTotal counts messages processed by the interceptor chain,
+// labeled by JSON-RPC method and direction ("in" or "out").
+var messagesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "starlight_messages_total",
+	Help: "Total number of Starlight messages processed, by method and direction.",
+}, []string{"method", "direction"})
+
+// messageDuration observes how long message handling/sending took, labeled
+// the same way as messagesTotal.
+var messageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "starlight_message_duration_seconds",
+	Help:    "Time spent handling or sending a Starlight message, by method and direction.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"method", "direction"})
+
+// RegisterMetrics registers the Prometheus collectors used by
+// MetricsInterceptor and MetricsSendInterceptor against reg. Call it once
+// during startup, e.g. with prometheus.DefaultRegisterer.
+func RegisterMetrics(reg prometheus.Registerer) error {
+	if err := reg.Register(messagesTotal); err != nil {
+		return fmt.Errorf("failed to register starlight_messages_total: %w", err)
+	}
+	if err := reg.Register(messageDuration); err != nil {
+		return fmt.Errorf("failed to register starlight_message_duration_seconds: %w", err)
+	}
+	return nil
+}
+
+// MetricsInterceptor records starlight_messages_total and
+// starlight_message_duration_seconds for every inbound message.
+func MetricsInterceptor() Interceptor {
+	return func(ctx context.Context, msg *Message, next Handler) error {
+		start := time.Now()
+		err := next(ctx, msg)
+		messagesTotal.WithLabelValues(msg.Method, "in").Inc()
+		messageDuration.WithLabelValues(msg.Method, "in").Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// MetricsSendInterceptor records starlight_messages_total and
+// starlight_message_duration_seconds for every outbound message.
+func MetricsSendInterceptor() SendInterceptor {
+	return func(ctx context.Context, msg *Message, next Sender) error {
+		start := time.Now()
+		err := next(ctx, msg)
+		messagesTotal.WithLabelValues(msg.Method, "out").Inc()
+		messageDuration.WithLabelValues(msg.Method, "out").Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+// msgCarrier adapts Message.Traceparent to propagation.TextMapCarrier so
+// the W3C Trace Context propagator can read and write it.
+type msgCarrier struct {
+	msg *Message
+}
+
+func (c msgCarrier) Get(key string) string {
+	if key == "traceparent" {
+		return c.msg.Traceparent
+	}
+	return ""
+}
+
+func (c msgCarrier) Set(key, value string) {
+	if key == "traceparent" {
+		c.msg.Traceparent = value
+	}
+}
+
+func (c msgCarrier) Keys() []string {
+	return []string{"traceparent"}
+}
+
+// TracingInterceptor starts a span for every inbound message, linked to the
+// Hub's span via the message's Traceparent field if present.
+func TracingInterceptor(tracer trace.Tracer) Interceptor {
+	return func(ctx context.Context, msg *Message, next Handler) error {
+		ctx = propagation.TraceContext{}.Extract(ctx, msgCarrier{msg})
+		ctx, span := tracer.Start(ctx, msg.Method)
+		defer span.End()
+		return next(ctx, msg)
+	}
+}
+
+// TracingSendInterceptor starts a span for every outbound message and
+// injects it into the message's Traceparent field so the Hub can link its
+// own span to it.
+func TracingSendInterceptor(tracer trace.Tracer) SendInterceptor {
+	return func(ctx context.Context, msg *Message, next Sender) error {
+		ctx, span := tracer.Start(ctx, msg.Method)
+		defer span.End()
+		propagation.TraceContext{}.Inject(ctx, msgCarrier{msg})
+		return next(ctx, msg)
+	}
+}
+
+// JWTVerifyInterceptor verifies the AuthToken carried on inbound
+// starlight.pre_check payloads against ks before OnPreCheck runs, so a Hub
+// impersonator can't trigger pre-check handling (and a possible hijack)
+// without a valid token. Messages for other methods pass through
+// unverified.
+func JWTVerifyInterceptor(ks KeySource) Interceptor {
+	return func(ctx context.Context, msg *Message, next Handler) error {
+		if msg.Method != "starlight.pre_check" {
+			return next(ctx, msg)
+		}
+
+		var params PreCheckParams
+		if err := msg.ParseParams(&params); err != nil {
+			return fmt.Errorf("jwt verify: failed to parse pre_check params: %w", err)
+		}
+		if _, err := ValidateTokenWithKeySource(params.AuthToken, ks); err != nil {
+			return fmt.Errorf("jwt verify: rejecting pre_check: %w", err)
+		}
+		return next(ctx, msg)
+	}
+}
+
+// RateLimitInterceptor limits inbound message handling to rps messages per
+// second per JSON-RPC method, with burst allowed above that rate. Methods
+// exceeding their limit are rejected with an error instead of dispatched.
+func RateLimitInterceptor(rps float64, burst int) Interceptor {
+	var mu sync.Mutex
+	limiters := make(map[string]*rate.Limiter)
+
+	limiterFor := func(method string) *rate.Limiter {
+		mu.Lock()
+		defer mu.Unlock()
+		l, ok := limiters[method]
+		if !ok {
+			l = rate.NewLimiter(rate.Limit(rps), burst)
+			limiters[method] = l
+		}
+		return l
+	}
+
+	return func(ctx context.Context, msg *Message, next Handler) error {
+		if !limiterFor(msg.Method).Allow() {
+			return fmt.Errorf("rate limit exceeded for method %q", msg.Method)
+		}
+		return next(ctx, msg)
+	}
+}
@@ -0,0 +1,202 @@
+package starlight
+
+import (
+	"context"
+	"testing"
+)
+
+func TestChainHandlerRunsOutermostFirst(t *testing.T) {
+	var order []string
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, msg *Message, next Handler) error {
+			order = append(order, name)
+			return next(ctx, msg)
+		}
+	}
+
+	h := chainHandler([]Interceptor{record("first"), record("second")}, func(ctx context.Context, msg *Message) error {
+		order = append(order, "terminal")
+		return nil
+	})
+
+	if err := h(context.Background(), &Message{}); err != nil {
+		t.Fatalf("chained handler returned error: %v", err)
+	}
+
+	want := []string{"first", "second", "terminal"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i, name := range want {
+		if order[i] != name {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestChainHandlerShortCircuitsOnError(t *testing.T) {
+	var ranSecond, ranTerminal bool
+
+	boom := func(ctx context.Context, msg *Message, next Handler) error {
+		return errBoom
+	}
+	second := func(ctx context.Context, msg *Message, next Handler) error {
+		ranSecond = true
+		return next(ctx, msg)
+	}
+
+	h := chainHandler([]Interceptor{boom, second}, func(ctx context.Context, msg *Message) error {
+		ranTerminal = true
+		return nil
+	})
+
+	if err := h(context.Background(), &Message{}); err != errBoom {
+		t.Fatalf("err = %v, want errBoom", err)
+	}
+	if ranSecond || ranTerminal {
+		t.Error("chainHandler should stop the chain when an interceptor errors")
+	}
+}
+
+func TestChainSenderRunsOutermostFirst(t *testing.T) {
+	var order []string
+	record := func(name string) SendInterceptor {
+		return func(ctx context.Context, msg *Message, next Sender) error {
+			order = append(order, name)
+			return next(ctx, msg)
+		}
+	}
+
+	send := chainSender([]SendInterceptor{record("first"), record("second")}, func(ctx context.Context, msg *Message) error {
+		order = append(order, "terminal")
+		return nil
+	})
+
+	if err := send(context.Background(), &Message{}); err != nil {
+		t.Fatalf("chained sender returned error: %v", err)
+	}
+
+	want := []string{"first", "second", "terminal"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i, name := range want {
+		if order[i] != name {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+}
+
+func TestChainHandlerEmptyIsTerminal(t *testing.T) {
+	called := false
+	h := chainHandler(nil, func(ctx context.Context, msg *Message) error {
+		called = true
+		return nil
+	})
+	if err := h(context.Background(), &Message{}); err != nil {
+		t.Fatalf("h: %v", err)
+	}
+	if !called {
+		t.Error("chainHandler with no interceptors should call the terminal directly")
+	}
+}
+
+func TestJWTVerifyInterceptorRejectsInvalidToken(t *testing.T) {
+	ks := StaticHMAC([]byte("secret"))
+	interceptor := JWTVerifyInterceptor(ks)
+
+	msg, err := NewMessage("starlight.pre_check", PreCheckParams{AuthToken: "not-a-jwt"})
+	if err != nil {
+		t.Fatalf("NewMessage: %v", err)
+	}
+
+	called := false
+	err = interceptor(context.Background(), msg, func(ctx context.Context, msg *Message) error {
+		called = true
+		return nil
+	})
+	if err == nil {
+		t.Fatal("expected JWTVerifyInterceptor to reject an invalid token")
+	}
+	if called {
+		t.Error("next should not run when the token is rejected")
+	}
+}
+
+func TestJWTVerifyInterceptorAllowsValidToken(t *testing.T) {
+	token, err := GenerateToken("secret", "sentinel-a", 3600)
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	interceptor := JWTVerifyInterceptor(StaticHMAC([]byte("secret")))
+	msg, err := NewMessage("starlight.pre_check", PreCheckParams{AuthToken: token})
+	if err != nil {
+		t.Fatalf("NewMessage: %v", err)
+	}
+
+	called := false
+	err = interceptor(context.Background(), msg, func(ctx context.Context, msg *Message) error {
+		called = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected valid token to pass, got %v", err)
+	}
+	if !called {
+		t.Error("next should run when the token is valid")
+	}
+}
+
+func TestJWTVerifyInterceptorIgnoresOtherMethods(t *testing.T) {
+	interceptor := JWTVerifyInterceptor(StaticHMAC([]byte("secret")))
+	msg, err := NewMessage("starlight.pulse", nil)
+	if err != nil {
+		t.Fatalf("NewMessage: %v", err)
+	}
+
+	called := false
+	err = interceptor(context.Background(), msg, func(ctx context.Context, msg *Message) error {
+		called = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error for non-pre_check method: %v", err)
+	}
+	if !called {
+		t.Error("JWTVerifyInterceptor should pass through methods other than starlight.pre_check unverified")
+	}
+}
+
+func TestRateLimitInterceptorBlocksBurstOverflow(t *testing.T) {
+	interceptor := RateLimitInterceptor(1, 1)
+	msg := &Message{Method: "starlight.pulse"}
+
+	terminal := func(ctx context.Context, msg *Message) error { return nil }
+
+	if err := interceptor(context.Background(), msg, terminal); err != nil {
+		t.Fatalf("first call within burst should be allowed, got %v", err)
+	}
+	if err := interceptor(context.Background(), msg, terminal); err == nil {
+		t.Fatal("second call exceeding rps/burst should be rejected")
+	}
+}
+
+func TestRateLimitInterceptorTracksMethodsIndependently(t *testing.T) {
+	interceptor := RateLimitInterceptor(1, 1)
+	terminal := func(ctx context.Context, msg *Message) error { return nil }
+
+	if err := interceptor(context.Background(), &Message{Method: "starlight.a"}, terminal); err != nil {
+		t.Fatalf("starlight.a: %v", err)
+	}
+	if err := interceptor(context.Background(), &Message{Method: "starlight.b"}, terminal); err != nil {
+		t.Fatalf("starlight.b should have its own limiter, got %v", err)
+	}
+}
+
+// errBoom is a sentinel error for chain short-circuit tests.
+var errBoom = &testError{"boom"}
+
+type testError struct{ msg string }
+
+func (e *testError) Error() string { return e.msg }
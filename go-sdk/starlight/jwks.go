@@ -0,0 +1,243 @@
+package starlight
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// KeySource resolves the verification key and algorithm for a given key ID.
+// Implementations that don't distinguish keys by kid (e.g. a single shared
+// secret) may ignore the kid argument.
+type KeySource interface {
+	// Key returns the verification key and its JWT algorithm ("HS256",
+	// "RS256", or "ES256") for the given kid.
+	Key(kid string) (key crypto.PublicKey, alg string, err error)
+}
+
+// staticHMAC is a KeySource backed by a single shared secret.
+type staticHMAC struct {
+	secret []byte
+}
+
+// StaticHMAC returns a KeySource that always verifies with the given secret
+// using HS256, regardless of the token's kid.
+func StaticHMAC(secret []byte) KeySource {
+	return &staticHMAC{secret: secret}
+}
+
+func (s *staticHMAC) Key(kid string) (crypto.PublicKey, string, error) {
+	return s.secret, "HS256", nil
+}
+
+// staticRSA is a KeySource backed by a single RSA public key.
+type staticRSA struct {
+	pub *rsa.PublicKey
+}
+
+// StaticRSA returns a KeySource that always verifies with the given RSA
+// public key using RS256, regardless of the token's kid.
+func StaticRSA(pub *rsa.PublicKey) KeySource {
+	return &staticRSA{pub: pub}
+}
+
+func (s *staticRSA) Key(kid string) (crypto.PublicKey, string, error) {
+	return s.pub, "RS256", nil
+}
+
+// jwk is a single entry in a JWKS "keys" array.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwksDoc struct {
+	Keys []jwk `json:"keys"`
+}
+
+// jwksEntry is a decoded, cached JWKS key.
+type jwksEntry struct {
+	key crypto.PublicKey
+	alg string
+}
+
+// jwksSource is a KeySource that fetches and caches keys from a JWKS
+// endpoint, refreshing them in the background.
+type jwksSource struct {
+	url    string
+	client *http.Client
+
+	mu   sync.RWMutex
+	keys map[string]jwksEntry
+
+	stop chan struct{}
+}
+
+// JWKS returns a KeySource that fetches keys from the given JWKS URL and
+// refreshes its cache every refresh interval. An initial fetch is performed
+// synchronously so the first ValidateTokenWithKeySource call doesn't race
+// the background refresher.
+func JWKS(url string, refresh time.Duration) (KeySource, error) {
+	j := &jwksSource{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+		keys:   make(map[string]jwksEntry),
+		stop:   make(chan struct{}),
+	}
+
+	if err := j.fetch(); err != nil {
+		return nil, fmt.Errorf("initial JWKS fetch failed: %w", err)
+	}
+
+	go j.refreshLoop(refresh)
+
+	return j, nil
+}
+
+// Close stops the background refresh goroutine.
+func (j *jwksSource) Close() {
+	close(j.stop)
+}
+
+func (j *jwksSource) refreshLoop(refresh time.Duration) {
+	ticker := time.NewTicker(refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			j.fetch()
+		case <-j.stop:
+			return
+		}
+	}
+}
+
+// fetch retrieves the JWKS document and replaces the decoded key cache.
+func (j *jwksSource) fetch() error {
+	resp, err := j.client.Get(j.url)
+	if err != nil {
+		return fmt.Errorf("jwks fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("jwks read: %w", err)
+	}
+
+	var doc jwksDoc
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Errorf("jwks parse: %w", err)
+	}
+
+	keys := make(map[string]jwksEntry, len(doc.Keys))
+	for _, k := range doc.Keys {
+		key, alg, err := decodeJWK(k)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = jwksEntry{key: key, alg: alg}
+	}
+
+	j.mu.Lock()
+	j.keys = keys
+	j.mu.Unlock()
+
+	return nil
+}
+
+// decodeJWK decodes a single JWKS entry into a crypto.PublicKey.
+func decodeJWK(k jwk) (crypto.PublicKey, string, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, "", fmt.Errorf("decode n: %w", err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, "", fmt.Errorf("decode e: %w", err)
+		}
+		pub := &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+		alg := k.Alg
+		if alg == "" {
+			alg = "RS256"
+		}
+		return pub, alg, nil
+
+	case "EC":
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, "", fmt.Errorf("decode x: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, "", fmt.Errorf("decode y: %w", err)
+		}
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, "", fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		pub := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		alg := k.Alg
+		if alg == "" {
+			alg = "ES256"
+		}
+		return pub, alg, nil
+
+	default:
+		return nil, "", fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+}
+
+// Key looks up a cached key by kid, fetching once more on a cache miss
+// before giving up.
+func (j *jwksSource) Key(kid string) (crypto.PublicKey, string, error) {
+	j.mu.RLock()
+	entry, ok := j.keys[kid]
+	j.mu.RUnlock()
+	if ok {
+		return entry.key, entry.alg, nil
+	}
+
+	if err := j.fetch(); err != nil {
+		return nil, "", err
+	}
+
+	j.mu.RLock()
+	entry, ok = j.keys[kid]
+	j.mu.RUnlock()
+	if !ok {
+		return nil, "", fmt.Errorf("no key found for kid %q", kid)
+	}
+	return entry.key, entry.alg, nil
+}
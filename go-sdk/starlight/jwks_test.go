@@ -0,0 +1,121 @@
+package starlight
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func jwksRSAKey(kid string) jwk {
+	return jwk{
+		Kty: "RSA",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString([]byte{0x01, 0x00, 0x01, 0x02}),
+		E:   base64.RawURLEncoding.EncodeToString([]byte{0x01, 0x00, 0x01}),
+	}
+}
+
+func TestJWKSFetchesKeyOnCacheMiss(t *testing.T) {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&fetches, 1)
+		doc := jwksDoc{Keys: []jwk{}}
+		if n >= 2 {
+			// The key only exists starting with the second fetch, so the
+			// first Key() call's cache-miss retry is what picks it up.
+			doc.Keys = []jwk{jwksRSAKey("key-1")}
+		}
+		json.NewEncoder(w).Encode(doc)
+	}))
+	defer server.Close()
+
+	ks, err := JWKS(server.URL, time.Hour)
+	if err != nil {
+		t.Fatalf("JWKS: %v", err)
+	}
+	defer ks.(*jwksSource).Close()
+
+	if atomic.LoadInt32(&fetches) != 1 {
+		t.Fatalf("fetches after JWKS() = %d, want 1 (synchronous initial fetch)", fetches)
+	}
+
+	_, alg, err := ks.Key("key-1")
+	if err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+	if alg != "RS256" {
+		t.Errorf("alg = %q, want RS256", alg)
+	}
+	if atomic.LoadInt32(&fetches) != 2 {
+		t.Errorf("fetches after cache-miss Key() = %d, want 2 (one retry fetch)", fetches)
+	}
+}
+
+func TestJWKSKeyStillMissingAfterRetryFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jwksDoc{Keys: []jwk{jwksRSAKey("other-key")}})
+	}))
+	defer server.Close()
+
+	ks, err := JWKS(server.URL, time.Hour)
+	if err != nil {
+		t.Fatalf("JWKS: %v", err)
+	}
+	defer ks.(*jwksSource).Close()
+
+	if _, _, err := ks.Key("missing-key"); err == nil {
+		t.Fatal("expected Key for an unknown kid to fail even after a retry fetch")
+	}
+}
+
+func TestJWKSRefreshLoopPicksUpRotatedKey(t *testing.T) {
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&fetches, 1)
+		kid := fmt.Sprintf("key-%d", n)
+		json.NewEncoder(w).Encode(jwksDoc{Keys: []jwk{jwksRSAKey(kid)}})
+	}))
+	defer server.Close()
+
+	ks, err := JWKS(server.URL, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("JWKS: %v", err)
+	}
+	defer ks.(*jwksSource).Close()
+
+	if _, _, err := ks.Key("key-1"); err != nil {
+		t.Fatalf("Key(key-1) right after initial fetch: %v", err)
+	}
+
+	// Inspect the cache directly rather than via Key(), whose cache-miss
+	// fetch would find a rotated key without the background refresher.
+	j := ks.(*jwksSource)
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		j.mu.RLock()
+		_, hasOriginal := j.keys["key-1"]
+		cached := len(j.keys)
+		j.mu.RUnlock()
+		if !hasOriginal && cached > 0 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("background refresh never picked up the rotated key")
+}
+
+func TestJWKSInitialFetchErrorFailsConstructor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if _, err := JWKS(server.URL, time.Hour); err == nil {
+		t.Fatal("expected JWKS to fail when the initial fetch errors")
+	}
+}
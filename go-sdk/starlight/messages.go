@@ -14,6 +14,16 @@ type Message struct {
 	ID      string          `json:"id,omitempty"`
 	Result  json.RawMessage `json:"result,omitempty"`
 	Error   *RPCError       `json:"error,omitempty"`
+
+	// Seq is the MessageStore sequence number assigned to an outbound
+	// message, echoed back by the Hub in a starlight.ack to confirm
+	// delivery. Zero for messages that were never queued.
+	Seq uint64 `json:"seq,omitempty"`
+
+	// Traceparent carries a W3C Trace Context traceparent value across the
+	// wire, letting TracingInterceptor/TracingSendInterceptor link a
+	// Sentinel's spans to the Hub's. Empty when tracing isn't in use.
+	Traceparent string `json:"traceparent,omitempty"`
 }
 
 // RPCError represents a JSON-RPC 2.0 error.
@@ -30,14 +40,75 @@ type RegistrationParams struct {
 	Capabilities []string `json:"capabilities,omitempty"`
 	Selectors    []string `json:"selectors,omitempty"`
 	AuthToken    string   `json:"authToken,omitempty"`
+
+	// Codecs lists the content types this sentinel can decode, in
+	// preference order, so the Hub can pick one in its challenge response.
+	Codecs []string `json:"codecs,omitempty"`
+
+	// ResumeSeq is the highest MessageStore sequence number this sentinel
+	// has already had acknowledged, so the Hub can dedupe replayed
+	// messages across reconnects.
+	ResumeSeq uint64 `json:"resume_seq,omitempty"`
+}
+
+// ChallengeResponseParams answers the Hub's registration challenge with the
+// expected response, completing the handshake started by registration.
+type ChallengeResponseParams struct {
+	Response string `json:"response"`
+}
+
+// AckParams contains parameters for the Hub's delivery acknowledgement of
+// a queued message.
+type AckParams struct {
+	Seq uint64 `json:"seq"`
+}
+
+// PeerSendParams carries a payload relayed between two Sentinels via the Hub.
+type PeerSendParams struct {
+	To      string          `json:"to"`
+	From    string          `json:"from"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// PeerOfferParams advertises a direct listen address to a peer, relayed
+// via the Hub, proposing an upgrade from the relay path to a direct link.
+type PeerOfferParams struct {
+	To         string `json:"to"`
+	From       string `json:"from"`
+	ListenAddr string `json:"listenAddr"`
+}
+
+// PeerAnswerParams is the relayed reply to a PeerOfferParams, optionally
+// advertising the answering side's own listen address.
+type PeerAnswerParams struct {
+	To         string `json:"to"`
+	From       string `json:"from"`
+	ListenAddr string `json:"listenAddr,omitempty"`
+}
+
+// PeerJoinedParams notifies a Sentinel that another Sentinel has
+// registered with the Hub, keeping knownPeers current between this
+// Sentinel's own (re)connects.
+type PeerJoinedParams struct {
+	Name string `json:"name"`
+}
+
+// PeerLeftParams notifies a Sentinel that another Sentinel has
+// disconnected from the Hub.
+type PeerLeftParams struct {
+	Name string `json:"name"`
 }
 
 // PreCheckParams contains parameters sent by Hub during pre-check.
 type PreCheckParams struct {
-	Command    CommandInfo      `json:"command"`
+	Command    CommandInfo       `json:"command"`
 	Blocking   []BlockingElement `json:"blocking,omitempty"`
-	Screenshot string           `json:"screenshot,omitempty"`
-	URL        string           `json:"url,omitempty"`
+	Screenshot string            `json:"screenshot,omitempty"`
+	URL        string            `json:"url,omitempty"`
+
+	// AuthToken, if the Hub is configured to sign pre-checks, is verified
+	// by JWTVerifyInterceptor before OnPreCheck runs.
+	AuthToken string `json:"authToken,omitempty"`
 }
 
 // CommandInfo describes the pending command.
@@ -133,7 +204,8 @@ func (m *Message) Marshal() ([]byte, error) {
 	return json.Marshal(m)
 }
 
-// ParseMessage deserializes JSON bytes into a Message.
+// ParseMessage deserializes JSON bytes into a Message. It always assumes
+// JSON; use a negotiated Codec's Unmarshal for codec-aware parsing.
 func ParseMessage(data []byte) (*Message, error) {
 	var msg Message
 	if err := json.Unmarshal(data, &msg); err != nil {
@@ -0,0 +1,269 @@
+package starlight
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// peerListenPath is the HTTP path the direct-peer listener serves the
+// WebSocket upgrade on.
+const peerListenPath = "/starlight/peer"
+
+// peerFrame is the wire format for a direct peer-to-peer link, deliberately
+// simpler than Message: direct links carry only payloads, not JSON-RPC
+// methods or IDs.
+type peerFrame struct {
+	From    string          `json:"from"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// peerHello is the first frame sent over a freshly dialed direct link so
+// the accepting side learns who connected.
+type peerHello struct {
+	From string `json:"from"`
+}
+
+var peerUpgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+}
+
+// directPeerConn pairs a direct-link connection with the mutex
+// gorilla/websocket requires callers to hold around concurrent writers;
+// reads are only ever done from directPeerReadLoop, so no read lock is
+// needed.
+type directPeerConn struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+}
+
+func (d *directPeerConn) WriteJSON(v any) error {
+	d.writeMu.Lock()
+	defer d.writeMu.Unlock()
+	return d.conn.WriteJSON(v)
+}
+
+// SendToPeer delivers payload to the named peer. If a direct link to that
+// peer is already up, it's sent straight across; otherwise the payload is
+// relayed through the Hub via starlight.peer_send, and, for a known peer
+// this Sentinel hasn't already offered a direct link to, a
+// starlight.peer_offer is sent alongside it to propose an upgrade.
+func (s *Sentinel) SendToPeer(name string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal peer payload: %w", err)
+	}
+
+	if dp := s.getDirectPeer(name); dp != nil {
+		if err := dp.WriteJSON(peerFrame{From: s.Name, Payload: data}); err == nil {
+			return nil
+		}
+		s.dropDirectPeerIfCurrent(name, dp.conn)
+	}
+
+	if err := s.sendMessage("starlight.peer_send", PeerSendParams{To: name, From: s.Name, Payload: data}); err != nil {
+		return err
+	}
+
+	s.offerDirectUpgrade(name)
+	return nil
+}
+
+// Peers returns the names of sentinels the Hub most recently reported as
+// connected, in no particular order.
+func (s *Sentinel) Peers() []string {
+	s.peersMu.Lock()
+	defer s.peersMu.Unlock()
+
+	peers := make([]string, 0, len(s.knownPeers))
+	for name := range s.knownPeers {
+		peers = append(peers, name)
+	}
+	return peers
+}
+
+// addKnownPeer records name as connected, e.g. on a starlight.peer_joined
+// notification, so offerDirectUpgrade can target peers that connect after
+// this Sentinel's own last (re)connect rather than only the set returned
+// at registration time.
+func (s *Sentinel) addKnownPeer(name string) {
+	s.peersMu.Lock()
+	s.knownPeers[name] = true
+	s.peersMu.Unlock()
+}
+
+// removeKnownPeer forgets name, e.g. on a starlight.peer_left
+// notification, clearing it from offeredPeers so a later rejoin gets a
+// fresh peer_offer, and tearing down any direct link still open to it.
+func (s *Sentinel) removeKnownPeer(name string) {
+	s.peersMu.Lock()
+	delete(s.knownPeers, name)
+	delete(s.offeredPeers, name)
+	s.peersMu.Unlock()
+
+	if dp := s.getDirectPeer(name); dp != nil {
+		s.dropDirectPeerIfCurrent(name, dp.conn)
+	}
+}
+
+// offerDirectUpgrade sends a peer_offer to name at most once, proposing a
+// direct link in place of the Hub relay. No-op if PeerListenAddr is unset,
+// name isn't a known peer, or an offer was already sent.
+func (s *Sentinel) offerDirectUpgrade(name string) {
+	if s.PeerListenAddr == "" {
+		return
+	}
+
+	s.peersMu.Lock()
+	if !s.knownPeers[name] || s.offeredPeers[name] {
+		s.peersMu.Unlock()
+		return
+	}
+	s.offeredPeers[name] = true
+	s.peersMu.Unlock()
+
+	if err := s.sendMessage("starlight.peer_offer", PeerOfferParams{To: name, From: s.Name, ListenAddr: s.PeerListenAddr}); err != nil {
+		s.Logger.Printf("[%s] Failed to offer direct link to %s: %v", s.Name, name, err)
+	}
+}
+
+// dialDirectPeer opens a direct WebSocket link to a peer's listen address
+// and, on success, registers it for future SendToPeer calls and begins
+// reading from it. Failures are logged and otherwise ignored: the Hub
+// relay remains available as a fallback.
+//
+// Only the lexicographically larger of the two names dials; the other
+// side is expected to accept the resulting connection via its own
+// listener. Without this tie-break, an offer/answer exchange between two
+// Sentinels that both set PeerListenAddr has each side dialing the other,
+// leaving one of the two connections (and its read loop) orphaned.
+func (s *Sentinel) dialDirectPeer(peerName, addr string) {
+	if addr == "" || s.Name < peerName {
+		return
+	}
+	if s.getDirectPeer(peerName) != nil {
+		return
+	}
+
+	url := fmt.Sprintf("ws://%s%s", addr, peerListenPath)
+	dialer := websocket.Dialer{HandshakeTimeout: 5 * time.Second}
+
+	conn, _, err := dialer.Dial(url, nil)
+	if err != nil {
+		s.Logger.Printf("[%s] Direct link to %s (%s) failed: %v", s.Name, peerName, addr, err)
+		return
+	}
+
+	if err := conn.WriteJSON(peerHello{From: s.Name}); err != nil {
+		s.Logger.Printf("[%s] Direct link handshake to %s failed: %v", s.Name, peerName, err)
+		conn.Close()
+		return
+	}
+
+	s.setDirectPeer(peerName, conn)
+	s.Logger.Printf("[%s] Direct link established with %s", s.Name, peerName)
+	go s.directPeerReadLoop(peerName, conn)
+}
+
+// startPeerListener serves the direct-peer WebSocket upgrade on
+// PeerListenAddr, if set. The listener runs until ctx is cancelled. No-op
+// if PeerListenAddr is empty.
+func (s *Sentinel) startPeerListener(ctx context.Context) error {
+	if s.PeerListenAddr == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(peerListenPath, func(w http.ResponseWriter, r *http.Request) {
+		conn, err := peerUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			s.Logger.Printf("[%s] Peer upgrade failed: %v", s.Name, err)
+			return
+		}
+
+		var hello peerHello
+		if err := conn.ReadJSON(&hello); err != nil || hello.From == "" {
+			s.Logger.Printf("[%s] Peer handshake failed: %v", s.Name, err)
+			conn.Close()
+			return
+		}
+
+		s.setDirectPeer(hello.From, conn)
+		s.Logger.Printf("[%s] Accepted direct link from %s", s.Name, hello.From)
+		go s.directPeerReadLoop(hello.From, conn)
+	})
+
+	server := &http.Server{Addr: s.PeerListenAddr, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			s.Logger.Printf("[%s] Peer listener stopped: %v", s.Name, err)
+		}
+	}()
+
+	return nil
+}
+
+// directPeerReadLoop forwards frames arriving over a direct peer link to
+// OnPeerMessage until the link errors or closes, at which point it's
+// dropped (if it's still the registered link for peerName) and future
+// sends to peerName fall back to the Hub relay.
+func (s *Sentinel) directPeerReadLoop(peerName string, conn *websocket.Conn) {
+	defer s.dropDirectPeerIfCurrent(peerName, conn)
+
+	for {
+		var frame peerFrame
+		if err := conn.ReadJSON(&frame); err != nil {
+			s.Logger.Printf("[%s] Direct link to %s closed: %v", s.Name, peerName, err)
+			return
+		}
+		if s.OnPeerMessage != nil {
+			s.OnPeerMessage(frame.From, frame.Payload)
+		}
+	}
+}
+
+func (s *Sentinel) getDirectPeer(name string) *directPeerConn {
+	s.peersMu.Lock()
+	defer s.peersMu.Unlock()
+	return s.directPeers[name]
+}
+
+// setDirectPeer registers conn as the direct link for name, closing
+// whatever connection (if any) it replaces so a second registration for
+// the same peer can never leak the first one's connection or read loop.
+func (s *Sentinel) setDirectPeer(name string, conn *websocket.Conn) {
+	dp := &directPeerConn{conn: conn}
+
+	s.peersMu.Lock()
+	old := s.directPeers[name]
+	s.directPeers[name] = dp
+	s.peersMu.Unlock()
+
+	if old != nil && old.conn != conn {
+		old.conn.Close()
+	}
+}
+
+// dropDirectPeerIfCurrent closes conn and removes it from directPeers, but
+// only if conn is still the registered link for name. This guards against
+// a stale connection's read loop evicting a newer one that's since
+// replaced it in setDirectPeer.
+func (s *Sentinel) dropDirectPeerIfCurrent(name string, conn *websocket.Conn) {
+	s.peersMu.Lock()
+	if dp, ok := s.directPeers[name]; ok && dp.conn == conn {
+		delete(s.directPeers, name)
+	}
+	s.peersMu.Unlock()
+	conn.Close()
+}
@@ -0,0 +1,183 @@
+package starlight
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// dialTestConn opens a real *websocket.Conn against an httptest server so
+// setDirectPeer/dropDirectPeerIfCurrent can be exercised against the same
+// type they operate on in production.
+func dialTestConn(t *testing.T) (*websocket.Conn, func()) {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		server.Close()
+		t.Fatalf("Dial: %v", err)
+	}
+	return conn, func() {
+		conn.Close()
+		server.Close()
+	}
+}
+
+func TestKnownPeersAndPeersList(t *testing.T) {
+	s := NewSentinel("sentinel-a", 1)
+
+	s.addKnownPeer("sentinel-b")
+	s.addKnownPeer("sentinel-c")
+
+	peers := s.Peers()
+	if len(peers) != 2 {
+		t.Fatalf("Peers() = %v, want 2 entries", peers)
+	}
+
+	s.removeKnownPeer("sentinel-b")
+	peers = s.Peers()
+	if len(peers) != 1 || peers[0] != "sentinel-c" {
+		t.Fatalf("Peers() after remove = %v, want [sentinel-c]", peers)
+	}
+}
+
+func TestRemoveKnownPeerClearsOffered(t *testing.T) {
+	s := NewSentinel("sentinel-a", 1)
+	s.addKnownPeer("sentinel-b")
+	s.offeredPeers["sentinel-b"] = true
+
+	s.removeKnownPeer("sentinel-b")
+
+	if s.offeredPeers["sentinel-b"] {
+		t.Error("removeKnownPeer should clear offeredPeers so a rejoin gets a fresh offer")
+	}
+}
+
+func TestOfferDirectUpgradeNoopWithoutListenAddr(t *testing.T) {
+	s := NewSentinel("sentinel-a", 1)
+	s.addKnownPeer("sentinel-b")
+
+	// PeerListenAddr is unset, so this must return without sending a
+	// peer_offer or marking the peer as offered.
+	s.offerDirectUpgrade("sentinel-b")
+
+	if s.offeredPeers["sentinel-b"] {
+		t.Error("offerDirectUpgrade should no-op when PeerListenAddr is unset")
+	}
+}
+
+func TestOfferDirectUpgradeNoopForUnknownPeer(t *testing.T) {
+	s := NewSentinel("sentinel-a", 1)
+	s.PeerListenAddr = "127.0.0.1:0"
+
+	s.offerDirectUpgrade("sentinel-b")
+
+	if s.offeredPeers["sentinel-b"] {
+		t.Error("offerDirectUpgrade should no-op for a peer that isn't known")
+	}
+}
+
+func TestDialDirectPeerTieBreakSkipsSmallerName(t *testing.T) {
+	s := NewSentinel("a-sentinel", 1)
+
+	// "a-sentinel" < "b-sentinel", so s must defer to the other side
+	// dialing rather than attempting a connection itself.
+	s.dialDirectPeer("b-sentinel", "127.0.0.1:1")
+
+	if s.getDirectPeer("b-sentinel") != nil {
+		t.Error("dialDirectPeer should not establish a link when this side loses the tie-break")
+	}
+}
+
+func TestDialDirectPeerSkipsEmptyAddr(t *testing.T) {
+	s := NewSentinel("z-sentinel", 1)
+
+	s.dialDirectPeer("a-sentinel", "")
+
+	if s.getDirectPeer("a-sentinel") != nil {
+		t.Error("dialDirectPeer should not dial when no listen address was offered")
+	}
+}
+
+func TestSetDirectPeerClosesReplacedConn(t *testing.T) {
+	s := NewSentinel("sentinel-a", 1)
+
+	oldConn, oldCleanup := dialTestConn(t)
+	defer oldCleanup()
+	newConn, newCleanup := dialTestConn(t)
+	defer newCleanup()
+
+	s.setDirectPeer("sentinel-b", oldConn)
+	s.setDirectPeer("sentinel-b", newConn)
+
+	if dp := s.getDirectPeer("sentinel-b"); dp == nil || dp.conn != newConn {
+		t.Fatal("setDirectPeer should register the newest connection")
+	}
+	if err := oldConn.WriteMessage(websocket.TextMessage, []byte("x")); err == nil {
+		t.Error("setDirectPeer should have closed the connection it replaced")
+	}
+}
+
+func TestDropDirectPeerIfCurrentOnlyDropsMatchingConn(t *testing.T) {
+	s := NewSentinel("sentinel-a", 1)
+
+	staleConn, staleCleanup := dialTestConn(t)
+	defer staleCleanup()
+	currentConn, currentCleanup := dialTestConn(t)
+	defer currentCleanup()
+
+	s.setDirectPeer("sentinel-b", staleConn)
+	s.setDirectPeer("sentinel-b", currentConn)
+
+	// Simulate a stale read loop's deferred cleanup firing after the map
+	// entry has already been replaced: it must not evict currentConn.
+	s.dropDirectPeerIfCurrent("sentinel-b", staleConn)
+
+	if dp := s.getDirectPeer("sentinel-b"); dp == nil || dp.conn != currentConn {
+		t.Fatal("dropDirectPeerIfCurrent with a stale conn should not evict the current link")
+	}
+
+	s.dropDirectPeerIfCurrent("sentinel-b", currentConn)
+	if s.getDirectPeer("sentinel-b") != nil {
+		t.Error("dropDirectPeerIfCurrent with the current conn should evict it")
+	}
+}
+
+func TestSendToPeerConcurrentWritesDontRace(t *testing.T) {
+	s := NewSentinel("sentinel-a", 1)
+
+	conn, cleanup := dialTestConn(t)
+	defer cleanup()
+	s.setDirectPeer("sentinel-b", conn)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := s.SendToPeer("sentinel-b", map[string]int{"i": i}); err != nil {
+				t.Errorf("SendToPeer: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
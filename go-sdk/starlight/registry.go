@@ -0,0 +1,173 @@
+package starlight
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+)
+
+// HubEndpoint describes one Hub a Sentinel can connect to.
+type HubEndpoint struct {
+	// URL is the Hub's dial address (ws://, wss://, grpc://, or grpcs://).
+	URL string
+
+	// Priority orders endpoints to try first; lower values are tried
+	// before higher ones, matching Sentinel.Priority's convention.
+	Priority int
+
+	// Weight breaks ties between endpoints of equal Priority: endpoints
+	// with a higher Weight are tried first among that group.
+	Weight int
+
+	// Region is an optional hint for operator-facing logging and metrics;
+	// it doesn't affect connection order.
+	Region string
+}
+
+// Registry resolves the set of Hubs a Sentinel may connect to, and can
+// notify callers when that set changes so Start can re-resolve and, if
+// needed, fail over without restarting the Sentinel.
+type Registry interface {
+	// Resolve returns the currently known Hub endpoints.
+	Resolve(ctx context.Context) ([]HubEndpoint, error)
+
+	// Watch returns a channel that receives the updated endpoint set
+	// whenever it changes. Implementations that can't watch for changes
+	// may return a channel that never fires.
+	Watch(ctx context.Context) (<-chan []HubEndpoint, error)
+}
+
+// orderEndpoints sorts endpoints by ascending Priority, then by descending
+// Weight within each priority group, so Start tries the most preferred
+// endpoints first.
+func orderEndpoints(endpoints []HubEndpoint) []HubEndpoint {
+	ordered := make([]HubEndpoint, len(endpoints))
+	copy(ordered, endpoints)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Priority != ordered[j].Priority {
+			return ordered[i].Priority < ordered[j].Priority
+		}
+		return ordered[i].Weight > ordered[j].Weight
+	})
+	return ordered
+}
+
+// staticRegistry is a Registry over a fixed, never-changing endpoint list.
+type staticRegistry struct {
+	endpoints []HubEndpoint
+}
+
+// StaticRegistry returns a Registry that always resolves to the given Hub
+// URLs, in the order given, all at the same priority and weight. Pass it to
+// Start to connect to one or more fixed Hub URLs.
+func StaticRegistry(urls ...string) Registry {
+	endpoints := make([]HubEndpoint, len(urls))
+	for i, url := range urls {
+		endpoints[i] = HubEndpoint{URL: url, Priority: 0, Weight: 1}
+	}
+	return &staticRegistry{endpoints: endpoints}
+}
+
+func (r *staticRegistry) Resolve(ctx context.Context) ([]HubEndpoint, error) {
+	return r.endpoints, nil
+}
+
+func (r *staticRegistry) Watch(ctx context.Context) (<-chan []HubEndpoint, error) {
+	// The endpoint set is fixed, so this channel simply never fires.
+	return make(chan []HubEndpoint), nil
+}
+
+// DNSRegistry resolves Hub endpoints from a DNS SRV record, using the
+// record's priority and weight directly as HubEndpoint.Priority/Weight.
+type DNSRegistry struct {
+	// Service, Proto, and Name are passed to net.LookupSRV, e.g.
+	// ("starlight", "tcp", "hub.example.com").
+	Service string
+	Proto   string
+	Name    string
+
+	// Scheme is prefixed to each resolved target:port, e.g. "ws" or "wss".
+	Scheme string
+
+	// RefreshInterval controls how often Watch re-queries DNS. Defaults
+	// to 30 seconds if zero.
+	RefreshInterval time.Duration
+}
+
+func (r *DNSRegistry) Resolve(ctx context.Context) ([]HubEndpoint, error) {
+	_, addrs, err := net.LookupSRV(r.Service, r.Proto, r.Name)
+	if err != nil {
+		return nil, fmt.Errorf("SRV lookup failed: %w", err)
+	}
+
+	endpoints := make([]HubEndpoint, len(addrs))
+	for i, addr := range addrs {
+		target := addr.Target
+		if len(target) > 0 && target[len(target)-1] == '.' {
+			target = target[:len(target)-1]
+		}
+		endpoints[i] = HubEndpoint{
+			URL:      fmt.Sprintf("%s://%s:%d", r.Scheme, target, addr.Port),
+			Priority: int(addr.Priority),
+			Weight:   int(addr.Weight),
+		}
+	}
+	return endpoints, nil
+}
+
+func (r *DNSRegistry) Watch(ctx context.Context) (<-chan []HubEndpoint, error) {
+	interval := r.RefreshInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	ch := make(chan []HubEndpoint, 1)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var last []HubEndpoint
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current, err := r.Resolve(ctx)
+				if err != nil {
+					continue
+				}
+				if !sameEndpoints(last, current) {
+					last = current
+					select {
+					case ch <- current:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// sameEndpoints reports whether two endpoint sets are equal, ignoring
+// order, for change detection in polling Watch implementations.
+func sameEndpoints(a, b []HubEndpoint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[HubEndpoint]int, len(a))
+	for _, e := range a {
+		seen[e]++
+	}
+	for _, e := range b {
+		seen[e]--
+		if seen[e] < 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,234 @@
+package starlight
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	consulapi "github.com/hashicorp/consul/api"
+	"github.com/hashicorp/mdns"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// MDNSRegistry discovers Hubs advertised on the local network via
+// multicast DNS, for deployments where Sentinel and Hub run on the same
+// LAN without a central discovery service.
+type MDNSRegistry struct {
+	// Service is the mDNS service name to browse, e.g. "_starlight._tcp".
+	Service string
+
+	// Domain defaults to "local." if empty.
+	Domain string
+
+	// Timeout bounds each browse; defaults to 2 seconds if zero.
+	Timeout time.Duration
+
+	// Scheme is prefixed to each resolved host:port, e.g. "ws".
+	Scheme string
+}
+
+func (r *MDNSRegistry) Resolve(ctx context.Context) ([]HubEndpoint, error) {
+	timeout := r.Timeout
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+
+	entries := make(chan *mdns.ServiceEntry, 16)
+	params := &mdns.QueryParam{
+		Service: r.Service,
+		Domain:  r.Domain,
+		Timeout: timeout,
+		Entries: entries,
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- mdns.Query(params)
+		// mdns.Query never closes Entries; close it so the loop below ends
+		// once the browse times out.
+		close(entries)
+	}()
+
+	var endpoints []HubEndpoint
+	for {
+		select {
+		case entry, ok := <-entries:
+			if !ok {
+				if err := <-done; err != nil {
+					return nil, fmt.Errorf("mdns query failed: %w", err)
+				}
+				return endpoints, nil
+			}
+			host := entry.Host
+			if entry.AddrV4 != nil {
+				host = entry.AddrV4.String()
+			} else if entry.AddrV6 != nil {
+				host = entry.AddrV6.String()
+			}
+			endpoints = append(endpoints, HubEndpoint{
+				URL: fmt.Sprintf("%s://%s:%d", r.Scheme, host, entry.Port),
+			})
+		case <-ctx.Done():
+			return endpoints, ctx.Err()
+		}
+	}
+}
+
+func (r *MDNSRegistry) Watch(ctx context.Context) (<-chan []HubEndpoint, error) {
+	ch := make(chan []HubEndpoint, 1)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+
+		var last []HubEndpoint
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current, err := r.Resolve(ctx)
+				if err != nil {
+					continue
+				}
+				if !sameEndpoints(last, current) {
+					last = current
+					select {
+					case ch <- current:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// ConsulRegistry discovers Hubs registered as healthy instances of a
+// Consul service.
+type ConsulRegistry struct {
+	Client *consulapi.Client
+
+	// Service is the Consul service name to query.
+	Service string
+
+	// Tag filters to instances carrying this tag, if non-empty.
+	Tag string
+
+	// Scheme is prefixed to each resolved address:port, e.g. "ws".
+	Scheme string
+}
+
+func (r *ConsulRegistry) Resolve(ctx context.Context) ([]HubEndpoint, error) {
+	entries, _, err := r.Client.Health().Service(r.Service, r.Tag, true, (&consulapi.QueryOptions{}).WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("consul service lookup failed: %w", err)
+	}
+
+	endpoints := make([]HubEndpoint, 0, len(entries))
+	for _, entry := range entries {
+		addr := entry.Service.Address
+		if addr == "" {
+			addr = entry.Node.Address
+		}
+		endpoints = append(endpoints, HubEndpoint{
+			URL:    fmt.Sprintf("%s://%s:%d", r.Scheme, addr, entry.Service.Port),
+			Weight: entry.Service.Weights.Passing,
+		})
+	}
+	return endpoints, nil
+}
+
+func (r *ConsulRegistry) Watch(ctx context.Context) (<-chan []HubEndpoint, error) {
+	ch := make(chan []HubEndpoint, 1)
+	go func() {
+		defer close(ch)
+		var lastIndex uint64
+		var last []HubEndpoint
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+			opts := (&consulapi.QueryOptions{WaitIndex: lastIndex, WaitTime: 5 * time.Minute}).WithContext(ctx)
+			entries, meta, err := r.Client.Health().Service(r.Service, r.Tag, true, opts)
+			if err != nil {
+				select {
+				case <-time.After(time.Second):
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			lastIndex = meta.LastIndex
+
+			current := make([]HubEndpoint, 0, len(entries))
+			for _, entry := range entries {
+				addr := entry.Service.Address
+				if addr == "" {
+					addr = entry.Node.Address
+				}
+				current = append(current, HubEndpoint{
+					URL:    fmt.Sprintf("%s://%s:%d", r.Scheme, addr, entry.Service.Port),
+					Weight: entry.Service.Weights.Passing,
+				})
+			}
+			if !sameEndpoints(last, current) {
+				last = current
+				select {
+				case ch <- current:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// EtcdRegistry discovers Hubs from JSON-encoded HubEndpoint values stored
+// under an etcd key prefix.
+type EtcdRegistry struct {
+	Client *clientv3.Client
+
+	// Prefix is the etcd key prefix holding one JSON HubEndpoint per key.
+	Prefix string
+}
+
+func (r *EtcdRegistry) Resolve(ctx context.Context) ([]HubEndpoint, error) {
+	resp, err := r.Client.Get(ctx, r.Prefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("etcd get failed: %w", err)
+	}
+
+	endpoints := make([]HubEndpoint, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var ep HubEndpoint
+		if err := json.Unmarshal(kv.Value, &ep); err != nil {
+			continue
+		}
+		endpoints = append(endpoints, ep)
+	}
+	return endpoints, nil
+}
+
+func (r *EtcdRegistry) Watch(ctx context.Context) (<-chan []HubEndpoint, error) {
+	ch := make(chan []HubEndpoint, 1)
+	go func() {
+		defer close(ch)
+		watchCh := r.Client.Watch(ctx, r.Prefix, clientv3.WithPrefix())
+		for range watchCh {
+			current, err := r.Resolve(ctx)
+			if err != nil {
+				continue
+			}
+			select {
+			case ch <- current:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
@@ -0,0 +1,78 @@
+package starlight
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOrderEndpointsByPriorityThenWeight(t *testing.T) {
+	endpoints := []HubEndpoint{
+		{URL: "a", Priority: 1, Weight: 10},
+		{URL: "b", Priority: 0, Weight: 5},
+		{URL: "c", Priority: 0, Weight: 20},
+		{URL: "d", Priority: 1, Weight: 1},
+	}
+
+	ordered := orderEndpoints(endpoints)
+
+	want := []string{"c", "b", "a", "d"}
+	for i, url := range want {
+		if ordered[i].URL != url {
+			t.Fatalf("ordered[%d].URL = %q, want %q (full order: %+v)", i, ordered[i].URL, url, ordered)
+		}
+	}
+
+	if endpoints[0].URL != "a" {
+		t.Error("orderEndpoints should not mutate its input slice")
+	}
+}
+
+func TestStaticRegistryResolve(t *testing.T) {
+	reg := StaticRegistry("ws://hub-1", "ws://hub-2")
+
+	endpoints, err := reg.Resolve(context.Background())
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if len(endpoints) != 2 {
+		t.Fatalf("Resolve() = %d endpoints, want 2", len(endpoints))
+	}
+	if endpoints[0].URL != "ws://hub-1" || endpoints[1].URL != "ws://hub-2" {
+		t.Errorf("Resolve() = %+v, want order preserved", endpoints)
+	}
+	for _, ep := range endpoints {
+		if ep.Priority != 0 || ep.Weight != 1 {
+			t.Errorf("endpoint %+v, want Priority=0 Weight=1", ep)
+		}
+	}
+}
+
+func TestStaticRegistryWatchNeverFires(t *testing.T) {
+	reg := StaticRegistry("ws://hub-1")
+
+	ch, err := reg.Watch(context.Background())
+	if err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+	select {
+	case v := <-ch:
+		t.Fatalf("expected Watch channel to never fire, got %+v", v)
+	default:
+	}
+}
+
+func TestSameEndpoints(t *testing.T) {
+	a := []HubEndpoint{{URL: "x", Priority: 1}, {URL: "y", Priority: 2}}
+	b := []HubEndpoint{{URL: "y", Priority: 2}, {URL: "x", Priority: 1}}
+	c := []HubEndpoint{{URL: "x", Priority: 1}, {URL: "z", Priority: 2}}
+
+	if !sameEndpoints(a, b) {
+		t.Error("sameEndpoints should ignore order")
+	}
+	if sameEndpoints(a, c) {
+		t.Error("sameEndpoints should report different sets as different")
+	}
+	if sameEndpoints(a, append(b, HubEndpoint{URL: "z"})) {
+		t.Error("sameEndpoints should report different lengths as different")
+	}
+}
@@ -7,8 +7,6 @@ import (
 	"log"
 	"sync"
 	"time"
-
-	"github.com/gorilla/websocket"
 )
 
 // Sentinel represents a Starlight Protocol sentinel agent.
@@ -28,6 +26,10 @@ type Sentinel struct {
 	// AuthToken is the JWT token for authentication (optional if Hub doesn't require auth).
 	AuthToken string
 
+	// authKeySource resolves keys for validating RS256/ES256/HS256 tokens,
+	// e.g. peer tokens checked via VerifyPeerToken. Set via WithAuthKeySource.
+	authKeySource KeySource
+
 	// OnPreCheck is called when the Hub sends a pre-check request.
 	// Return Clear(), Wait(ms), or Hijack(reason) response.
 	OnPreCheck func(params PreCheckParams, msgID string) error
@@ -35,6 +37,73 @@ type Sentinel struct {
 	// OnEntropyStream is called when the Hub broadcasts entropy data.
 	OnEntropyStream func(params EntropyStreamParams)
 
+	// OnHubChange is called whenever Start connects to a different Hub
+	// endpoint than the one it was previously connected to, e.g. after a
+	// registry-driven failover.
+	OnHubChange func(endpoint HubEndpoint)
+
+	// OnPeerMessage is called when a payload arrives from another
+	// Sentinel, whether relayed through the Hub or over a direct link.
+	OnPeerMessage func(from string, payload json.RawMessage)
+
+	// PeerListenAddr, if set, is the local address this Sentinel listens
+	// on for direct peer connections (e.g. "127.0.0.1:9100"). Leave empty
+	// to only ever exchange peer messages through the Hub relay.
+	PeerListenAddr string
+
+	// peersMu protects knownPeers, directPeers, and offeredPeers.
+	peersMu sync.Mutex
+
+	// knownPeers is the set of sentinel names the Hub has reported as
+	// connected: the full set at registration time, kept current
+	// afterward by starlight.peer_joined/starlight.peer_left
+	// notifications.
+	knownPeers map[string]bool
+
+	// directPeers holds upgraded, directly-connected peer links by name,
+	// each paired with the write mutex gorilla/websocket requires for
+	// concurrent writers.
+	directPeers map[string]*directPeerConn
+
+	// offeredPeers tracks peers we've already sent a peer_offer to, so we
+	// don't re-offer on every SendToPeer call.
+	offeredPeers map[string]bool
+
+	// peerListenerOnce ensures the direct-peer listener starts at most
+	// once across reconnects.
+	peerListenerOnce sync.Once
+
+	// lastHubURL is the URL Start last connected to, used to detect
+	// failover for OnHubChange.
+	lastHubURL string
+
+	// Codec controls how messages are serialized on the wire when using
+	// WSTransport. Defaults to JSONCodec. Negotiated automatically against
+	// the Hub during registration if the Hub returns a preferred codec in
+	// its challenge response, and carried forward across reconnects.
+	Codec Codec
+
+	// Transport dials the Hub. If nil, Start picks WSTransport or
+	// GRPCTransport from the hub URL scheme.
+	Transport Transport
+
+	// Store durably queues outbound messages so sends issued while
+	// disconnected aren't lost, and replays them in order on reconnect.
+	// Defaults to an unbounded MemoryStore.
+	Store MessageStore
+
+	// resumeSeq is the highest store sequence the Hub has acknowledged,
+	// sent as RegistrationParams.ResumeSeq so the Hub can dedupe replays.
+	resumeSeq uint64
+
+	// inInterceptors wraps every message handleMessage dispatches, in
+	// registration order. Registered via Use.
+	inInterceptors []Interceptor
+
+	// outInterceptors wraps every message writeMessage/writeControl sends,
+	// in registration order. Registered via UseSend.
+	outInterceptors []SendInterceptor
+
 	// Logger for sentinel operations. Defaults to standard log.
 	Logger *log.Logger
 
@@ -44,8 +113,8 @@ type Sentinel struct {
 	// ReconnectDelay is the time to wait before reconnecting. Default: 3 seconds.
 	ReconnectDelay time.Duration
 
-	// conn is the WebSocket connection to the Hub.
-	conn *websocket.Conn
+	// conn is the transport connection to the Hub.
+	conn Conn
 
 	// mu protects concurrent access to the connection.
 	mu sync.Mutex
@@ -69,16 +138,41 @@ func NewSentinel(name string, priority int) *Sentinel {
 		Priority:          priority,
 		Capabilities:      []string{"detection"},
 		Selectors:         []string{},
+		Codec:             JSONCodec{},
+		Store:             NewMemoryStore(0, 0),
 		HeartbeatInterval: 2 * time.Second,
 		ReconnectDelay:    3 * time.Second,
 		Logger:            log.Default(),
 		done:              make(chan struct{}),
+		knownPeers:        make(map[string]bool),
+		directPeers:       make(map[string]*directPeerConn),
+		offeredPeers:      make(map[string]bool),
 	}
 }
 
-// Start connects to the Hub and begins the sentinel lifecycle.
-// This method blocks until the context is cancelled or an unrecoverable error occurs.
-func (s *Sentinel) Start(ctx context.Context, hubURL string) error {
+// Start resolves Hub endpoints from registry and begins the sentinel
+// lifecycle, trying endpoints in priority/weight order and re-resolving
+// whenever the registry reports a change. This method blocks until the
+// context is cancelled or an unrecoverable error occurs. Use
+// StaticRegistry(hubURL) to connect to a single, fixed Hub URL.
+func (s *Sentinel) Start(ctx context.Context, registry Registry) error {
+	s.peerListenerOnce.Do(func() {
+		if err := s.startPeerListener(ctx); err != nil {
+			s.Logger.Printf("[%s] Failed to start peer listener: %v", s.Name, err)
+		}
+	})
+
+	watchCh, err := registry.Watch(ctx)
+	if err != nil {
+		s.Logger.Printf("[%s] Registry watch unavailable: %v", s.Name, err)
+		watchCh = nil
+	}
+
+	endpoints, err := registry.Resolve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to resolve hub endpoints: %w", err)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -88,28 +182,51 @@ func (s *Sentinel) Start(ctx context.Context, hubURL string) error {
 		default:
 		}
 
-		if err := s.connect(ctx, hubURL); err != nil {
-			s.Logger.Printf("[%s] Connection failed: %v, retrying in %v", s.Name, err, s.ReconnectDelay)
+		connected := false
+		for _, ep := range orderEndpoints(endpoints) {
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
-			case <-time.After(s.ReconnectDelay):
+			case <-s.done:
+				return nil
+			default:
+			}
+
+			if err := s.connect(ctx, ep.URL); err != nil {
+				s.Logger.Printf("[%s] Connection to %s failed: %v", s.Name, ep.URL, err)
 				continue
 			}
-		}
+			connected = true
 
-		// Run message loop
-		if err := s.messageLoop(ctx); err != nil {
-			s.Logger.Printf("[%s] Message loop error: %v", s.Name, err)
+			if ep.URL != s.lastHubURL {
+				s.lastHubURL = ep.URL
+				if s.OnHubChange != nil {
+					s.OnHubChange(ep)
+				}
+			}
+
+			if err := s.messageLoop(ctx); err != nil {
+				s.Logger.Printf("[%s] Message loop error: %v", s.Name, err)
+			}
+			s.disconnect()
+			break
 		}
 
-		s.disconnect()
+		if !connected {
+			s.Logger.Printf("[%s] All hub endpoints failed, retrying in %v", s.Name, s.ReconnectDelay)
+		}
 
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
+		case <-s.done:
+			return nil
+		case updated, ok := <-watchCh:
+			if ok {
+				s.Logger.Printf("[%s] Hub registry updated, re-resolving", s.Name)
+				endpoints = updated
+			}
 		case <-time.After(s.ReconnectDelay):
-			s.Logger.Printf("[%s] Reconnecting...", s.Name)
 		}
 	}
 }
@@ -120,22 +237,34 @@ func (s *Sentinel) Stop() {
 	s.disconnect()
 }
 
-// connect establishes WebSocket connection and registers with Hub.
+// connect dials the Hub via Transport and registers with it. s.mu is only
+// held for the brief updates to shared connection state, never across the
+// registration send or the handshake wait below: both go through
+// writeControl, which takes s.mu itself via send, and holding it here too
+// would self-deadlock (sync.Mutex isn't reentrant).
 func (s *Sentinel) connect(ctx context.Context, hubURL string) error {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
+	transport := s.Transport
+	if transport == nil {
+		transport = transportForURL(hubURL, s.Codec)
 	}
 
-	conn, _, err := dialer.DialContext(ctx, hubURL, nil)
+	conn, err := transport.Dial(ctx, hubURL)
 	if err != nil {
 		return fmt.Errorf("dial failed: %w", err)
 	}
 
+	s.mu.Lock()
 	s.conn = conn
 	s.isConnected = true
+	s.regID = fmt.Sprintf("reg-%d", time.Now().UnixNano())
+	s.ready = make(chan struct{})
+	s.mu.Unlock()
+
+	markFailed := func() {
+		s.mu.Lock()
+		s.isConnected = false
+		s.mu.Unlock()
+	}
 
 	regParams := RegistrationParams{
 		Layer:        s.Name,
@@ -143,23 +272,22 @@ func (s *Sentinel) connect(ctx context.Context, hubURL string) error {
 		Capabilities: s.Capabilities,
 		Selectors:    s.Selectors,
 		AuthToken:    s.AuthToken,
+		Codecs:       supportedCodecContentTypes(),
+		ResumeSeq:    s.resumeSeq,
 	}
 
 	// Send registration with unique ID for handshake tracking
-	s.regID = fmt.Sprintf("reg-%d", time.Now().UnixNano())
 	msg, err := NewMessage("starlight.registration", regParams)
 	if err != nil {
 		conn.Close()
-		s.isConnected = false
+		markFailed()
 		return fmt.Errorf("failed to create registration message: %w", err)
 	}
 	msg.ID = s.regID
 
-	s.ready = make(chan struct{})
-
-	if err := s.writeMessage(msg); err != nil {
+	if err := s.writeControl(msg); err != nil {
 		conn.Close()
-		s.isConnected = false
+		markFailed()
 		return fmt.Errorf("registration failed: %w", err)
 	}
 
@@ -169,19 +297,43 @@ func (s *Sentinel) connect(ctx context.Context, hubURL string) error {
 	select {
 	case <-s.ready:
 		s.Logger.Printf("[%s] Handshake Verified -> READY state achieved", s.Name)
+		s.replayPending()
 		return nil
 	case <-ctx.Done():
 		conn.Close()
-		s.isConnected = false
+		markFailed()
 		return ctx.Err()
 	case <-time.After(10 * time.Second):
 		conn.Close()
-		s.isConnected = false
+		markFailed()
 		return fmt.Errorf("handshake timeout")
 	}
 }
 
-// disconnect closes the WebSocket connection.
+// replayPending resends every message still sitting unacknowledged in
+// Store, in order, after a (re)connect.
+func (s *Sentinel) replayPending() {
+	pending, err := s.Store.Pending()
+	if err != nil {
+		s.Logger.Printf("[%s] Failed to load pending messages: %v", s.Name, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return
+	}
+	for _, msg := range pending {
+		if err := s.conn.Send(msg); err != nil {
+			s.Logger.Printf("[%s] Replay failed for seq %d: %v", s.Name, msg.Seq, err)
+			return
+		}
+	}
+}
+
+// disconnect closes the transport connection.
 func (s *Sentinel) disconnect() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -234,40 +386,51 @@ func (s *Sentinel) messageLoop(ctx context.Context) error {
 			return fmt.Errorf("connection lost")
 		}
 
-		// Set read deadline for responsiveness
-		conn.SetReadDeadline(time.Now().Add(30 * time.Second))
-
-		_, data, err := conn.ReadMessage()
+		msg, err := conn.Recv()
 		if err != nil {
-			return fmt.Errorf("read error: %w", err)
+			return err
 		}
 
-		msg, err := ParseMessage(data)
-		if err != nil {
-			s.Logger.Printf("[%s] Failed to parse message: %v", s.Name, err)
-			continue
-		}
-
-		s.handleMessage(msg)
+		s.handleMessage(ctx, msg)
 	}
 }
 
-// handleMessage routes incoming messages to appropriate handlers.
-func (s *Sentinel) handleMessage(msg *Message) {
+// handleMessage verifies handshake replies, then runs msg through the
+// inbound interceptor chain (see Use) terminating in dispatchMessage.
+func (s *Sentinel) handleMessage(ctx context.Context, msg *Message) {
 	// Phase 1 Security: Handle Handshake Responses (Registration Guard)
 	if msg.ID != "" {
 		if msg.ID == s.regID && msg.Result != nil {
 			var result struct {
-				Success   bool   `json:"success"`
-				Challenge string `json:"challenge"`
+				Success   bool     `json:"success"`
+				Challenge string   `json:"challenge"`
+				Codec     string   `json:"codec,omitempty"`
+				Peers     []string `json:"peers,omitempty"`
 			}
 			if err := json.Unmarshal(msg.Result, &result); err == nil && result.Success {
+				s.peersMu.Lock()
+				s.knownPeers = make(map[string]bool, len(result.Peers))
+				for _, peer := range result.Peers {
+					s.knownPeers[peer] = true
+				}
+				s.peersMu.Unlock()
+				if result.Codec != "" {
+					if codec, ok := codecByContentType(result.Codec); ok {
+						s.Logger.Printf("[%s] Negotiated codec: %s", s.Name, result.Codec)
+						s.Codec = codec
+						if switcher, ok := s.conn.(codecSwitcher); ok {
+							switcher.SetCodec(codec)
+						}
+					} else if s.Codec != nil {
+						s.Logger.Printf("[%s] Hub requested unsupported codec %q, keeping %s", s.Name, result.Codec, s.Codec.ContentType())
+					}
+				}
 				s.Logger.Printf("[%s] Handshake challenge received, responding...", s.Name)
 				s.handshakeID = fmt.Sprintf("chal-%d", time.Now().UnixNano())
 
 				challengeMsg, _ := NewMessage("starlight.challenge_response", ChallengeResponseParams{Response: result.Challenge})
 				challengeMsg.ID = s.handshakeID
-				s.writeMessage(challengeMsg)
+				s.writeControl(challengeMsg)
 			}
 			return
 		}
@@ -282,29 +445,95 @@ func (s *Sentinel) handleMessage(msg *Message) {
 		}
 	}
 
+	handler := chainHandler(s.inInterceptors, s.dispatchMessage)
+	if err := handler(ctx, msg); err != nil {
+		s.Logger.Printf("[%s] %v", s.Name, err)
+	}
+}
+
+// dispatchMessage routes an incoming message to its handler by method. It's
+// the terminal Handler of the inbound interceptor chain built in
+// handleMessage.
+func (s *Sentinel) dispatchMessage(ctx context.Context, msg *Message) error {
 	switch msg.Method {
 	case "starlight.pre_check":
+		var params PreCheckParams
+		if err := msg.ParseParams(&params); err != nil {
+			s.SendClear(msg.ID)
+			return fmt.Errorf("failed to parse pre_check params: %w", err)
+		}
 		if s.OnPreCheck != nil {
-			var params PreCheckParams
-			if err := msg.ParseParams(&params); err != nil {
-				s.Logger.Printf("[%s] Failed to parse pre_check params: %v", s.Name, err)
-				s.SendClear(msg.ID)
-				return
-			}
 			if err := s.OnPreCheck(params, msg.ID); err != nil {
-				s.Logger.Printf("[%s] OnPreCheck error: %v", s.Name, err)
+				return fmt.Errorf("OnPreCheck error: %w", err)
 			}
 		} else {
 			// Default: clear if no handler
 			s.SendClear(msg.ID)
 		}
 
+	case "starlight.ack":
+		var params AckParams
+		if err := msg.ParseParams(&params); err != nil {
+			return fmt.Errorf("failed to parse ack params: %w", err)
+		}
+		if err := s.Store.Ack(params.Seq); err != nil {
+			return fmt.Errorf("failed to ack seq %d: %w", params.Seq, err)
+		}
+		if params.Seq > s.resumeSeq {
+			s.resumeSeq = params.Seq
+		}
+
+	case "starlight.peer_send":
+		var params PeerSendParams
+		if err := msg.ParseParams(&params); err != nil {
+			return fmt.Errorf("failed to parse peer_send params: %w", err)
+		}
+		if s.OnPeerMessage != nil {
+			s.OnPeerMessage(params.From, params.Payload)
+		}
+
+	case "starlight.peer_offer":
+		var params PeerOfferParams
+		if err := msg.ParseParams(&params); err != nil {
+			return fmt.Errorf("failed to parse peer_offer params: %w", err)
+		}
+		if params.To != s.Name {
+			return nil
+		}
+		if s.PeerListenAddr != "" {
+			s.sendMessage("starlight.peer_answer", PeerAnswerParams{To: params.From, From: s.Name, ListenAddr: s.PeerListenAddr})
+		}
+		go s.dialDirectPeer(params.From, params.ListenAddr)
+
+	case "starlight.peer_answer":
+		var params PeerAnswerParams
+		if err := msg.ParseParams(&params); err != nil {
+			return fmt.Errorf("failed to parse peer_answer params: %w", err)
+		}
+		if params.To != s.Name {
+			return nil
+		}
+		go s.dialDirectPeer(params.From, params.ListenAddr)
+
+	case "starlight.peer_joined":
+		var params PeerJoinedParams
+		if err := msg.ParseParams(&params); err != nil {
+			return fmt.Errorf("failed to parse peer_joined params: %w", err)
+		}
+		s.addKnownPeer(params.Name)
+
+	case "starlight.peer_left":
+		var params PeerLeftParams
+		if err := msg.ParseParams(&params); err != nil {
+			return fmt.Errorf("failed to parse peer_left params: %w", err)
+		}
+		s.removeKnownPeer(params.Name)
+
 	case "starlight.entropy_stream":
 		if s.OnEntropyStream != nil {
 			var params EntropyStreamParams
 			if err := msg.ParseParams(&params); err != nil {
-				s.Logger.Printf("[%s] Failed to parse entropy params: %v", s.Name, err)
-				return
+				return fmt.Errorf("failed to parse entropy params: %w", err)
 			}
 			s.OnEntropyStream(params)
 		}
@@ -315,6 +544,7 @@ func (s *Sentinel) handleMessage(msg *Message) {
 			s.Logger.Printf("[%s] Received unknown method: %s", s.Name, msg.Method)
 		}
 	}
+	return nil
 }
 
 // SendClear sends a clear response to approve action execution.
@@ -351,9 +581,14 @@ func (s *Sentinel) SendContextUpdate(ctx map[string]any) error {
 	return s.sendMessage("starlight.context_update", ContextUpdateParams{Context: ctx})
 }
 
-// sendPulse sends a heartbeat message.
+// sendPulse sends a heartbeat message, bypassing Store since the Hub never
+// acks pulses.
 func (s *Sentinel) sendPulse() error {
-	return s.sendMessage("starlight.pulse", map[string]string{"layer": s.Name})
+	msg, err := NewMessage("starlight.pulse", map[string]string{"layer": s.Name})
+	if err != nil {
+		return err
+	}
+	return s.writeControl(msg)
 }
 
 // sendMessage sends a JSON-RPC message to the Hub.
@@ -374,21 +609,48 @@ func (s *Sentinel) sendResponse(id, method string, params any) error {
 	return s.writeMessage(msg)
 }
 
-// writeMessage sends a message over the WebSocket connection.
+// writeMessage durably queues msg, then runs it through the outbound
+// interceptor chain (see UseSend) terminating in send. If the Sentinel is
+// currently disconnected, the message stays queued and is replayed on the
+// next successful connect instead of failing outright.
+//
+// Use this only for application-facing sends the Hub acks via
+// starlight.ack (SendClear, SendWait, SendHijack, SendResume, SendAction,
+// SendContextUpdate). Handshake and heartbeat traffic goes through
+// writeControl instead: the Hub never acks it, so queuing it would grow
+// Store unboundedly and cause replayPending to resend a stale
+// registration/challenge on every reconnect.
 func (s *Sentinel) writeMessage(msg *Message) error {
+	seq, err := s.Store.Append(msg)
+	if err != nil {
+		return fmt.Errorf("failed to queue message: %w", err)
+	}
+	msg.Seq = seq
+
+	sender := chainSender(s.outInterceptors, s.send)
+	return sender(context.Background(), msg)
+}
+
+// writeControl sends msg straight through the outbound interceptor chain
+// without appending it to Store, for control-plane traffic (registration,
+// challenge_response, pulse) that the Hub never acks.
+func (s *Sentinel) writeControl(msg *Message) error {
+	sender := chainSender(s.outInterceptors, s.send)
+	return sender(context.Background(), msg)
+}
+
+// send is the terminal Sender of the outbound interceptor chain: it hands
+// msg to the live connection, or drops it silently if disconnected (msg
+// remains in Store for replay on reconnect).
+func (s *Sentinel) send(ctx context.Context, msg *Message) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	if s.conn == nil {
-		return fmt.Errorf("not connected")
-	}
-
-	data, err := json.Marshal(msg)
-	if err != nil {
-		return err
+		return nil
 	}
 
-	return s.conn.WriteMessage(websocket.TextMessage, data)
+	return s.conn.Send(msg)
 }
 
 // IsConnected returns whether the sentinel is currently connected to the Hub.
@@ -0,0 +1,47 @@
+package starlight
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// registrationResult builds a starlight.registration response Message
+// carrying the given peer list, matching what the Hub sends on the wire.
+func registrationResult(t *testing.T, regID string, peers []string) *Message {
+	t.Helper()
+
+	result, err := json.Marshal(map[string]any{
+		"success": true,
+		"peers":   peers,
+	})
+	if err != nil {
+		t.Fatalf("marshal result: %v", err)
+	}
+	return &Message{JSONRPC: "2.0", ID: regID, Result: result}
+}
+
+func TestHandleMessageResetsKnownPeersOnEmptyPeerList(t *testing.T) {
+	s := NewSentinel("sentinel-a", 1)
+	s.regID = "reg-1"
+	s.addKnownPeer("stale-peer")
+
+	s.handleMessage(context.Background(), registrationResult(t, "reg-1", []string{}))
+
+	if peers := s.Peers(); len(peers) != 0 {
+		t.Errorf("Peers() = %v, want empty after a registration result reporting zero peers", peers)
+	}
+}
+
+func TestHandleMessageResetsKnownPeersToLatestList(t *testing.T) {
+	s := NewSentinel("sentinel-a", 1)
+	s.regID = "reg-1"
+	s.addKnownPeer("stale-peer")
+
+	s.handleMessage(context.Background(), registrationResult(t, "reg-1", []string{"sentinel-b"}))
+
+	peers := s.Peers()
+	if len(peers) != 1 || peers[0] != "sentinel-b" {
+		t.Errorf("Peers() = %v, want [sentinel-b] (stale-peer dropped)", peers)
+	}
+}
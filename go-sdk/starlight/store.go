@@ -0,0 +1,357 @@
+package starlight
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// MessageStore durably queues outbound messages so they survive a dropped
+// connection and can be replayed, in order, once the Sentinel reconnects.
+type MessageStore interface {
+	// Append queues msg and returns the sequence number assigned to it.
+	Append(msg *Message) (seq uint64, err error)
+
+	// Ack removes the message at seq from the queue once the Hub has
+	// confirmed receipt.
+	Ack(seq uint64) error
+
+	// Pending returns all queued, unacknowledged, unexpired messages in
+	// the order they were appended.
+	Pending() ([]*Message, error)
+}
+
+// storeEntry is a queued message along with its bookkeeping.
+type storeEntry struct {
+	Seq     uint64   `json:"seq"`
+	Msg     *Message `json:"msg"`
+	Expires int64    `json:"expires,omitempty"` // UnixNano; 0 = no TTL
+}
+
+// MemoryStore is an in-memory MessageStore. It's the default for Sentinels
+// that don't need durability across process restarts.
+type MemoryStore struct {
+	mu      sync.Mutex
+	nextSeq uint64
+	order   []uint64
+	entries map[uint64]storeEntry
+
+	// MaxSize caps the number of pending messages; 0 means unbounded. Once
+	// exceeded, the oldest pending message is dropped to make room.
+	MaxSize int
+
+	// TTL expires a message if it's still pending after this long; 0
+	// means messages never expire on their own.
+	TTL time.Duration
+}
+
+// NewMemoryStore creates an empty MemoryStore with the given drop policy.
+func NewMemoryStore(maxSize int, ttl time.Duration) *MemoryStore {
+	return &MemoryStore{
+		order:   make([]uint64, 0),
+		entries: make(map[uint64]storeEntry),
+		MaxSize: maxSize,
+		TTL:     ttl,
+	}
+}
+
+func (s *MemoryStore) Append(msg *Message) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextSeq++
+	seq := s.nextSeq
+
+	var expires int64
+	if s.TTL > 0 {
+		expires = time.Now().Add(s.TTL).UnixNano()
+	}
+
+	s.entries[seq] = storeEntry{Seq: seq, Msg: msg, Expires: expires}
+	s.order = append(s.order, seq)
+
+	if s.MaxSize > 0 && len(s.order) > s.MaxSize {
+		dropped := s.order[0]
+		s.order = s.order[1:]
+		delete(s.entries, dropped)
+	}
+
+	return seq, nil
+}
+
+func (s *MemoryStore) Ack(seq uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[seq]; !ok {
+		return nil
+	}
+	delete(s.entries, seq)
+	for i, pending := range s.order {
+		if pending == seq {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Pending() ([]*Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UnixNano()
+	msgs := make([]*Message, 0, len(s.order))
+	live := s.order[:0]
+	for _, seq := range s.order {
+		entry, ok := s.entries[seq]
+		if !ok {
+			continue
+		}
+		if entry.Expires != 0 && entry.Expires < now {
+			delete(s.entries, seq)
+			continue
+		}
+		live = append(live, seq)
+		msgs = append(msgs, entry.Msg)
+	}
+	s.order = live
+
+	return msgs, nil
+}
+
+// FileStore is a file-backed MessageStore: an append-only log of "append"
+// and "ack" records, compacted periodically to drop acknowledged entries.
+type FileStore struct {
+	mu sync.Mutex
+
+	path    string
+	file    *os.File
+	nextSeq uint64
+	order   []uint64
+	entries map[uint64]storeEntry
+
+	// MaxSize and TTL apply the same drop policy as MemoryStore.
+	MaxSize int
+	TTL     time.Duration
+
+	// acksSinceCompaction triggers periodic compaction of the log file.
+	acksSinceCompaction int
+}
+
+// compactionThreshold is how many Ack calls accumulate before the log is
+// rewritten to drop acknowledged entries.
+const compactionThreshold = 100
+
+type logRecord struct {
+	Op    string     `json:"op"` // "append" or "ack"
+	Entry storeEntry `json:"entry,omitempty"`
+	Seq   uint64     `json:"seq,omitempty"`
+}
+
+// OpenFileStore opens (creating if necessary) a durable queue backed by the
+// log file at path, replaying it to rebuild in-memory state.
+func OpenFileStore(path string, maxSize int, ttl time.Duration) (*FileStore, error) {
+	fs := &FileStore{
+		path:    path,
+		order:   make([]uint64, 0),
+		entries: make(map[uint64]storeEntry),
+		MaxSize: maxSize,
+		TTL:     ttl,
+	}
+
+	if err := fs.replay(); err != nil {
+		return nil, fmt.Errorf("failed to replay message store log: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open message store log: %w", err)
+	}
+	fs.file = file
+
+	return fs, nil
+}
+
+// replay reads the existing log, if any, to rebuild pending entries and
+// nextSeq before the store starts appending to it.
+func (fs *FileStore) replay() error {
+	f, err := os.Open(fs.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec logRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue
+		}
+		switch rec.Op {
+		case "append":
+			// The logged message predates writeMessage assigning its Seq.
+			if rec.Entry.Msg != nil {
+				rec.Entry.Msg.Seq = rec.Entry.Seq
+			}
+			fs.entries[rec.Entry.Seq] = rec.Entry
+			fs.order = append(fs.order, rec.Entry.Seq)
+			if rec.Entry.Seq > fs.nextSeq {
+				fs.nextSeq = rec.Entry.Seq
+			}
+		case "ack":
+			delete(fs.entries, rec.Seq)
+			for i, seq := range fs.order {
+				if seq == rec.Seq {
+					fs.order = append(fs.order[:i], fs.order[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+func (fs *FileStore) appendRecord(rec logRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = fs.file.Write(data)
+	return err
+}
+
+func (fs *FileStore) Append(msg *Message) (uint64, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	fs.nextSeq++
+	seq := fs.nextSeq
+
+	var expires int64
+	if fs.TTL > 0 {
+		expires = time.Now().Add(fs.TTL).UnixNano()
+	}
+
+	entry := storeEntry{Seq: seq, Msg: msg, Expires: expires}
+	if err := fs.appendRecord(logRecord{Op: "append", Entry: entry}); err != nil {
+		return 0, fmt.Errorf("failed to append to message store log: %w", err)
+	}
+
+	fs.entries[seq] = entry
+	fs.order = append(fs.order, seq)
+
+	if fs.MaxSize > 0 && len(fs.order) > fs.MaxSize {
+		dropped := fs.order[0]
+		fs.order = fs.order[1:]
+		delete(fs.entries, dropped)
+		fs.appendRecord(logRecord{Op: "ack", Seq: dropped})
+	}
+
+	return seq, nil
+}
+
+func (fs *FileStore) Ack(seq uint64) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if _, ok := fs.entries[seq]; !ok {
+		return nil
+	}
+	if err := fs.appendRecord(logRecord{Op: "ack", Seq: seq}); err != nil {
+		return fmt.Errorf("failed to append ack to message store log: %w", err)
+	}
+
+	delete(fs.entries, seq)
+	for i, pending := range fs.order {
+		if pending == seq {
+			fs.order = append(fs.order[:i], fs.order[i+1:]...)
+			break
+		}
+	}
+
+	fs.acksSinceCompaction++
+	if fs.acksSinceCompaction >= compactionThreshold {
+		if err := fs.compact(); err != nil {
+			return fmt.Errorf("failed to compact message store log: %w", err)
+		}
+	}
+	return nil
+}
+
+// compact rewrites the log file to contain only the currently pending
+// entries, dropping the append/ack history that led to this state.
+func (fs *FileStore) compact() error {
+	tmpPath := fs.path + ".compact"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	for _, seq := range fs.order {
+		data, err := json.Marshal(logRecord{Op: "append", Entry: fs.entries[seq]})
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+		if _, err := tmp.Write(append(data, '\n')); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, fs.path); err != nil {
+		return err
+	}
+
+	fs.file.Close()
+	file, err := os.OpenFile(fs.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	fs.file = file
+	fs.acksSinceCompaction = 0
+	return nil
+}
+
+func (fs *FileStore) Pending() ([]*Message, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	now := time.Now().UnixNano()
+	msgs := make([]*Message, 0, len(fs.order))
+	live := fs.order[:0]
+	for _, seq := range fs.order {
+		entry, ok := fs.entries[seq]
+		if !ok {
+			continue
+		}
+		if entry.Expires != 0 && entry.Expires < now {
+			delete(fs.entries, seq)
+			continue
+		}
+		live = append(live, seq)
+		msgs = append(msgs, entry.Msg)
+	}
+	fs.order = live
+
+	return msgs, nil
+}
+
+// Close flushes and closes the underlying log file.
+func (fs *FileStore) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.file.Close()
+}
@@ -0,0 +1,149 @@
+package starlight
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreAppendAck(t *testing.T) {
+	store := NewMemoryStore(0, 0)
+
+	seq1, err := store.Append(&Message{Method: "m1"})
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	seq2, err := store.Append(&Message{Method: "m2"})
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if seq1 == seq2 {
+		t.Fatalf("expected distinct sequence numbers, got %d and %d", seq1, seq2)
+	}
+
+	pending, err := store.Pending()
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("Pending() = %d messages, want 2", len(pending))
+	}
+
+	if err := store.Ack(seq1); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+
+	pending, err = store.Pending()
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Method != "m2" {
+		t.Fatalf("Pending() after ack = %+v, want only m2", pending)
+	}
+}
+
+func TestMemoryStoreMaxSizeDrop(t *testing.T) {
+	store := NewMemoryStore(2, 0)
+
+	store.Append(&Message{Method: "m1"})
+	store.Append(&Message{Method: "m2"})
+	store.Append(&Message{Method: "m3"})
+
+	pending, err := store.Pending()
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) != 2 {
+		t.Fatalf("Pending() = %d messages, want 2", len(pending))
+	}
+	if pending[0].Method != "m2" || pending[1].Method != "m3" {
+		t.Fatalf("Pending() = %+v, want [m2 m3] (oldest dropped)", pending)
+	}
+}
+
+func TestMemoryStoreTTLExpiry(t *testing.T) {
+	store := NewMemoryStore(0, time.Millisecond)
+
+	if _, err := store.Append(&Message{Method: "m1"}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	pending, err := store.Pending()
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("Pending() = %d messages, want 0 (expired)", len(pending))
+	}
+}
+
+func TestFileStoreReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	fs, err := OpenFileStore(path, 0, 0)
+	if err != nil {
+		t.Fatalf("OpenFileStore: %v", err)
+	}
+
+	seq1, err := fs.Append(&Message{Method: "m1"})
+	if err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if _, err := fs.Append(&Message{Method: "m2"}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	if err := fs.Ack(seq1); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if err := fs.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	reopened, err := OpenFileStore(path, 0, 0)
+	if err != nil {
+		t.Fatalf("OpenFileStore (reopen): %v", err)
+	}
+	defer reopened.Close()
+
+	pending, err := reopened.Pending()
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Method != "m2" {
+		t.Fatalf("Pending() after replay = %+v, want only m2", pending)
+	}
+}
+
+func TestFileStoreCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.log")
+
+	fs, err := OpenFileStore(path, 0, 0)
+	if err != nil {
+		t.Fatalf("OpenFileStore: %v", err)
+	}
+	defer fs.Close()
+
+	for i := 0; i < compactionThreshold; i++ {
+		seq, err := fs.Append(&Message{Method: "m"})
+		if err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+		if err := fs.Ack(seq); err != nil {
+			t.Fatalf("Ack: %v", err)
+		}
+	}
+
+	if fs.acksSinceCompaction != 0 {
+		t.Errorf("acksSinceCompaction = %d, want 0 after compaction", fs.acksSinceCompaction)
+	}
+
+	pending, err := fs.Pending()
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("Pending() = %d messages, want 0", len(pending))
+	}
+}
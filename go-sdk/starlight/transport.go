@@ -0,0 +1,134 @@
+package starlight
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Transport dials a Hub URL and returns a Conn for exchanging Messages.
+// Sentinel picks an implementation based on the URL scheme ("ws://",
+// "wss://", "grpc://", "grpcs://") unless one is set explicitly.
+type Transport interface {
+	Dial(ctx context.Context, url string) (Conn, error)
+}
+
+// Conn is a transport-agnostic bidirectional Message stream.
+type Conn interface {
+	Send(msg *Message) error
+	Recv() (*Message, error)
+	Close() error
+}
+
+// codecSwitcher is implemented by Conns whose wire codec can be renegotiated
+// mid-session, such as wsConn after the registration handshake.
+type codecSwitcher interface {
+	SetCodec(codec Codec)
+	Codec() Codec
+}
+
+// transportForURL picks the default Transport for a Hub URL scheme. codec
+// seeds WSTransport.InitialCodec; it's ignored for grpc(s):// URLs, which
+// always speak protobuf.
+func transportForURL(url string, codec Codec) Transport {
+	switch {
+	case strings.HasPrefix(url, "grpc://"), strings.HasPrefix(url, "grpcs://"):
+		return GRPCTransport{}
+	default:
+		return WSTransport{InitialCodec: codec}
+	}
+}
+
+// WSTransport dials the Hub over WebSocket, encoding and decoding frames
+// with InitialCodec (JSONCodec if unset). This is the protocol's original
+// transport, now expressed as one Transport implementation among others.
+type WSTransport struct {
+	// InitialCodec is used for the registration handshake and for any
+	// messages exchanged before the Hub negotiates a different codec.
+	InitialCodec Codec
+}
+
+func (t WSTransport) Dial(ctx context.Context, url string) (Conn, error) {
+	codec := t.InitialCodec
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+
+	dialer := websocket.Dialer{
+		HandshakeTimeout: 10 * time.Second,
+	}
+
+	conn, _, err := dialer.DialContext(ctx, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("dial failed: %w", err)
+	}
+
+	return &wsConn{ws: conn, codec: codec}, nil
+}
+
+// wsConn adapts a gorilla websocket.Conn to Conn, using codec to frame
+// messages and to pick the WebSocket message type (text vs. binary).
+// codec is read from Send/Recv (called from the message loop and the
+// heartbeat goroutine) and written from SetCodec (called mid-session
+// during codec negotiation), so it's guarded by codecMu rather than
+// accessed directly.
+type wsConn struct {
+	ws *websocket.Conn
+
+	codecMu sync.RWMutex
+	codec   Codec
+}
+
+func (c *wsConn) Send(msg *Message) error {
+	codec := c.getCodec()
+	data, err := codec.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return c.ws.WriteMessage(codec.WSMessageType(), data)
+}
+
+func (c *wsConn) Recv() (*Message, error) {
+	c.ws.SetReadDeadline(time.Now().Add(30 * time.Second))
+	_, data, err := c.ws.ReadMessage()
+	if err != nil {
+		return nil, fmt.Errorf("read error: %w", err)
+	}
+
+	var msg Message
+	if err := c.getCodec().Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("failed to parse message: %w", err)
+	}
+	return &msg, nil
+}
+
+func (c *wsConn) Close() error {
+	return c.ws.Close()
+}
+
+func (c *wsConn) getCodec() Codec {
+	c.codecMu.RLock()
+	defer c.codecMu.RUnlock()
+	return c.codec
+}
+
+func (c *wsConn) SetCodec(codec Codec) {
+	c.codecMu.Lock()
+	defer c.codecMu.Unlock()
+	c.codec = codec
+}
+
+func (c *wsConn) Codec() Codec {
+	return c.getCodec()
+}
+
+// defaultTLSConfig is used for "grpcs://" URLs that don't configure their
+// own credentials via GRPCTransport.DialOptions.
+func defaultTLSConfig() *tls.Config {
+	return &tls.Config{}
+}